@@ -3,13 +3,19 @@ module github.com/gataky/dive
 go 1.23.3
 
 require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/atotto/clipboard v0.1.4
+	github.com/expr-lang/expr v1.17.8
 	github.com/gdamore/tcell/v2 v2.9.0
+	github.com/klauspost/compress v1.18.0
 	github.com/rivo/tview v0.42.0
+	github.com/santhosh-tekuri/jsonschema/v6 v6.0.3
 	github.com/tidwall/gjson v1.18.0
+	github.com/ulikunitz/xz v0.5.15
+	golang.org/x/text v0.28.0
 )
 
 require (
-	github.com/atotto/clipboard v0.1.4 // indirect
 	github.com/gdamore/encoding v1.0.1 // indirect
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-runewidth v0.0.16 // indirect
@@ -18,5 +24,4 @@ require (
 	github.com/tidwall/pretty v1.2.0 // indirect
 	golang.org/x/sys v0.35.0 // indirect
 	golang.org/x/term v0.34.0 // indirect
-	golang.org/x/text v0.28.0 // indirect
 )
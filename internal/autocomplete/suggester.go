@@ -3,16 +3,34 @@ package autocomplete
 import (
 	"strings"
 
+	"github.com/gataky/dive/internal/query"
 	"github.com/tidwall/gjson"
 )
 
 // GetSuggestions returns autocomplete suggestions for a given path
 func GetSuggestions(jsonData string, currentPath string) []string {
+	return suggest(jsonData, currentPath, false)
+}
+
+// GetExpandedSuggestions returns autocomplete suggestions for a path that
+// traverses strings holding JSON, suggesting the keys inside them
+func GetExpandedSuggestions(jsonData string, currentPath string) []string {
+	return suggest(jsonData, currentPath, true)
+}
+
+// suggest returns the suggestions for a path, expanding JSON strings on the
+// way if expand is set
+func suggest(jsonData string, currentPath string, expand bool) []string {
 	// Handle empty path - suggest top-level keys
 	if currentPath == "" {
 		return getTopLevelKeys(jsonData)
 	}
 
+	// A segment starting with @ is a modifier rather than a key
+	if suggestions, ok := getModifierSuggestions(currentPath); ok {
+		return suggestions
+	}
+
 	// Parse the current path to determine the base path and incomplete segment
 	basePath, incomplete := parsePathForAutocomplete(currentPath)
 
@@ -21,6 +39,9 @@ func GetSuggestions(jsonData string, currentPath string) []string {
 	if basePath == "" {
 		// We're at the top level
 		result = gjson.Parse(jsonData)
+	} else if expand {
+		expanded, _ := query.ExpandPath(jsonData, basePath)
+		result = gjson.Get(jsonData, expanded)
 	} else {
 		result = gjson.Get(jsonData, basePath)
 	}
@@ -72,6 +93,25 @@ func parsePathForAutocomplete(path string) (basePath, incomplete string) {
 	return path[:lastDot], path[lastDot+1:]
 }
 
+// getModifierSuggestions completes modifier names when the last segment of the
+// path, after a dot or pipe, starts with @
+func getModifierSuggestions(path string) ([]string, bool) {
+	separator := strings.LastIndexAny(path, ".|")
+	segment := path[separator+1:]
+	if !strings.HasPrefix(segment, "@") {
+		return nil, false
+	}
+
+	suggestions := []string{}
+	for _, modifier := range query.Modifiers {
+		if strings.HasPrefix(modifier.Name, segment) {
+			suggestions = append(suggestions, path[:separator+1]+modifier.Name)
+		}
+	}
+
+	return suggestions, true
+}
+
 // getTopLevelKeys returns all top-level keys from the JSON
 func getTopLevelKeys(jsonData string) []string {
 	result := gjson.Parse(jsonData)
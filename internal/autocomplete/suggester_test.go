@@ -221,3 +221,43 @@ func TestGetSuggestionsSpecialCharacters(t *testing.T) {
 		t.Errorf("Expected %v for prefix 'field', got %v", expected, suggestions)
 	}
 }
+
+func TestGetSuggestionsModifiers(t *testing.T) {
+	jsonData := `{"orders": [{"total": 1}]}`
+
+	suggestions := GetSuggestions(jsonData, "orders.#.total|@s")
+	expected := []string{"orders.#.total|@sum", "orders.#.total|@sort"}
+	if !reflect.DeepEqual(suggestions, expected) {
+		t.Errorf("Expected %v, got %v", expected, suggestions)
+	}
+
+	suggestions = GetSuggestions(jsonData, "orders.@cou")
+	if !reflect.DeepEqual(suggestions, []string{"orders.@count"}) {
+		t.Errorf("Expected [orders.@count], got %v", suggestions)
+	}
+
+	// A bare @ at the top level lists every modifier
+	suggestions = GetSuggestions(jsonData, "@")
+	if len(suggestions) < 20 || suggestions[0] != "@reverse" {
+		t.Errorf("Expected all modifiers starting with @reverse, got %v", suggestions)
+	}
+}
+
+func TestGetExpandedSuggestions(t *testing.T) {
+	jsonData := `{"event": {"body": "{\"user\": 1, \"type\": \"click\"}"}}`
+
+	if suggestions := GetSuggestions(jsonData, "event.body."); len(suggestions) != 0 {
+		t.Errorf("Expected no suggestions inside a string, got %v", suggestions)
+	}
+
+	suggestions := GetExpandedSuggestions(jsonData, "event.body.")
+	expected := []string{"event.body.user", "event.body.type"}
+	if !reflect.DeepEqual(suggestions, expected) {
+		t.Errorf("Expected %v, got %v", expected, suggestions)
+	}
+
+	suggestions = GetExpandedSuggestions(jsonData, "event.body.t")
+	if !reflect.DeepEqual(suggestions, []string{"event.body.type"}) {
+		t.Errorf("Expected [event.body.type], got %v", suggestions)
+	}
+}
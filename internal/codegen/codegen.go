@@ -0,0 +1,254 @@
+package codegen
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/gataky/dive/internal/schema"
+)
+
+// Language is a target language for generated type definitions
+type Language int
+
+const (
+	Go Language = iota
+	TypeScript
+	Python
+)
+
+// Languages lists every supported language in display order
+var Languages = []Language{Go, TypeScript, Python}
+
+// String returns the display name of the language
+func (l Language) String() string {
+	switch l {
+	case TypeScript:
+		return "TypeScript"
+	case Python:
+		return "Python"
+	default:
+		return "Go"
+	}
+}
+
+// FileExtension returns the conventional file extension for the language
+func (l Language) FileExtension() string {
+	switch l {
+	case TypeScript:
+		return ".ts"
+	case Python:
+		return ".py"
+	default:
+		return ".go"
+	}
+}
+
+// ParseLanguage returns the language with the given name, ignoring case
+func ParseLanguage(name string) (Language, error) {
+	for _, l := range Languages {
+		if strings.EqualFold(name, l.String()) {
+			return l, nil
+		}
+	}
+	switch strings.ToLower(name) {
+	case "ts":
+		return TypeScript, nil
+	case "py":
+		return Python, nil
+	}
+	return Go, fmt.Errorf("unknown language: %s", name)
+}
+
+// Generate returns type definitions describing node in the given language.
+// rootName names the type of the value itself; nested objects get types named
+// after the property they appear in.
+func Generate(lang Language, node *schema.Node, rootName string) string {
+	types := collectTypes(node, rootName)
+
+	switch lang {
+	case TypeScript:
+		return generateTypeScript(node, types)
+	case Python:
+		return generatePython(node, types)
+	default:
+		return generateGo(node, types)
+	}
+}
+
+// namedType is an object shape that is emitted as its own type definition
+type namedType struct {
+	name string
+	node *schema.Node
+}
+
+// typeSet holds the named types of a document in order of discovery
+type typeSet struct {
+	rootName string // Name of the type of the value itself
+	types    []namedType
+	names    map[*schema.Node]string
+	used     map[string]bool
+}
+
+// nameOf returns the type name assigned to an object node
+func (ts *typeSet) nameOf(node *schema.Node) string {
+	return ts.names[node]
+}
+
+// collectTypes assigns unique type names to every object node reachable from root
+func collectTypes(root *schema.Node, rootName string) *typeSet {
+	ts := &typeSet{
+		rootName: typeName(rootName),
+		names:    make(map[*schema.Node]string),
+		used:     make(map[string]bool),
+	}
+
+	name := ts.rootName
+	if single(root) != schema.Object {
+		// The root is emitted as a named alias of its type, so its name is taken
+		ts.used[name] = true
+		if root.Types&schema.Object != 0 {
+			name += "Object"
+		}
+	}
+	ts.collect(root, name)
+	return ts
+}
+
+// collect names node if it is an object, then descends into its properties and array elements
+func (ts *typeSet) collect(node *schema.Node, name string) {
+	if node == nil {
+		return
+	}
+
+	if node.Types&schema.Object != 0 {
+		unique := uniqueName(name, ts.used)
+		ts.names[node] = unique
+		ts.types = append(ts.types, namedType{name: unique, node: node})
+
+		for _, p := range node.Properties {
+			ts.collect(p.Node, typeName(p.Name))
+		}
+	}
+
+	if node.Types&schema.Array != 0 {
+		ts.collect(node.Items, singular(name))
+	}
+}
+
+// single returns the only JSON type of a node ignoring null, or 0 for unions
+func single(node *schema.Node) schema.Type {
+	types := node.Types &^ schema.Null
+	// Integers mixed with fractional numbers are numbers
+	if types&schema.Number != 0 {
+		types &^= schema.Integer
+	}
+	switch types {
+	case schema.Boolean, schema.Integer, schema.Number, schema.String, schema.Array, schema.Object:
+		return types
+	}
+	return 0
+}
+
+// nullable reports whether null was observed alongside other types
+func nullable(node *schema.Node) bool {
+	return node.Types&schema.Null != 0 && node.Types != schema.Null
+}
+
+// words splits a JSON key into words on separators and lower-to-upper case changes
+func words(key string) []string {
+	var result []string
+	var current []rune
+
+	flush := func() {
+		if len(current) > 0 {
+			result = append(result, string(current))
+			current = nil
+		}
+	}
+
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) ||
+			i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1])):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+
+	return result
+}
+
+// commonInitialisms are written in upper case in Go identifiers
+var commonInitialisms = map[string]bool{
+	"API": true, "ID": true, "URL": true, "URI": true, "HTTP": true, "HTTPS": true,
+	"JSON": true, "XML": true, "HTML": true, "UUID": true, "IP": true, "SQL": true,
+}
+
+// pascalCase converts a key to PascalCase, optionally upper casing common initialisms
+func pascalCase(key string, initialisms bool) string {
+	var b strings.Builder
+	for _, w := range words(key) {
+		upper := strings.ToUpper(w)
+		if initialisms && commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		runes := []rune(strings.ToLower(w))
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
+// snakeCase converts a key to snake_case
+func snakeCase(key string) string {
+	parts := words(key)
+	for i, w := range parts {
+		parts[i] = strings.ToLower(w)
+	}
+	return strings.Join(parts, "_")
+}
+
+// typeName converts a key into a type name, falling back to "Field" when it has no letters
+func typeName(key string) string {
+	name := pascalCase(key, true)
+	if name == "" {
+		return "Field"
+	}
+	if unicode.IsDigit([]rune(name)[0]) {
+		return "T" + name
+	}
+	return name
+}
+
+// singular derives an element type name from the name of its array
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"),
+		strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") &&
+		!strings.HasSuffix(name, "us") && len(name) > 1:
+		return name[:len(name)-1]
+	}
+	return name + "Item"
+}
+
+// uniqueName returns name, or name with a numeric suffix if it is already in used
+func uniqueName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	used[unique] = true
+	return unique
+}
@@ -0,0 +1,164 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gataky/dive/internal/schema"
+)
+
+const usersJSON = `{
+	"users": [
+		{"id": 1, "userName": "ada", "email": null, "address": {"city": "London"}, "tags": ["admin"]},
+		{"id": 2, "userName": "bob", "email": "bob@example.com", "score": 1.5, "tags": [], "2fa": true}
+	],
+	"total": 2
+}`
+
+func TestGenerateGo(t *testing.T) {
+	code := Generate(Go, schema.Infer(usersJSON), "Root")
+
+	expected := []string{
+		"type Root struct {\n\tUsers []User `json:\"users\"`\n\tTotal int64  `json:\"total\"`\n}",
+		"\tID       int64    `json:\"id\"`",
+		"\tUserName string   `json:\"userName\"`",
+		"\tEmail    *string  `json:\"email\"`",
+		"\tAddress  *Address `json:\"address,omitempty\"`",
+		"\tTags     []string `json:\"tags\"`",
+		"\tScore    *float64 `json:\"score,omitempty\"`",
+		"\tField2fa *bool    `json:\"2fa,omitempty\"`",
+		"type Address struct {\n\tCity string `json:\"city\"`\n}",
+	}
+	for _, e := range expected {
+		if !strings.Contains(code, e) {
+			t.Errorf("Expected Go code to contain %q, got:\n%s", e, code)
+		}
+	}
+}
+
+func TestGenerateGoRootArray(t *testing.T) {
+	code := Generate(Go, schema.Infer(`[{"a": 1}, {"a": 2}]`), "users")
+
+	expected := "type Users []User\n\ntype User struct {\n\tA int64 `json:\"a\"`\n}\n"
+	if code != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, code)
+	}
+}
+
+func TestGenerateGoMixedTypes(t *testing.T) {
+	code := Generate(Go, schema.Infer(`{"v": [1, "a"], "n": null, "e": []}`), "Root")
+
+	for _, e := range []string{"V []any", "N any", "E []any"} {
+		if !strings.Contains(code, e) {
+			t.Errorf("Expected Go code to contain %q, got:\n%s", e, code)
+		}
+	}
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	code := Generate(TypeScript, schema.Infer(usersJSON), "Root")
+
+	expected := []string{
+		"export interface Root {\n  users: User[];\n  total: number;\n}",
+		"  email: string | null;",
+		"  address?: Address;",
+		"  \"2fa\"?: boolean;",
+		"export interface Address {\n  city: string;\n}",
+	}
+	for _, e := range expected {
+		if !strings.Contains(code, e) {
+			t.Errorf("Expected TypeScript code to contain %q, got:\n%s", e, code)
+		}
+	}
+
+	code = Generate(TypeScript, schema.Infer(`[1, "a", null]`), "Root")
+	if code != "export type Root = (string | number | null)[];\n" {
+		t.Errorf("Expected union array alias, got:\n%s", code)
+	}
+}
+
+func TestGeneratePython(t *testing.T) {
+	code := Generate(Python, schema.Infer(usersJSON), "Root")
+
+	expected := []string{
+		"from typing import Optional\n",
+		"@dataclass\nclass Address:\n    city: str\n",
+		"    user_name: str  # \"userName\"\n",
+		"    email: Optional[str]\n",
+		"    tags: list[str]\n    address: Optional[Address] = None\n",
+		"    field_2fa: Optional[bool] = None  # \"2fa\"\n",
+		"@dataclass\nclass Root:\n    users: list[User]\n    total: int\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(code, e) {
+			t.Errorf("Expected Python code to contain %q, got:\n%s", e, code)
+		}
+	}
+
+	// Nested classes are defined before the classes using them
+	if strings.Index(code, "class User:") > strings.Index(code, "class Root:") {
+		t.Errorf("Expected User to be defined before Root, got:\n%s", code)
+	}
+}
+
+func TestGenerateUniqueTypeNames(t *testing.T) {
+	code := Generate(Go, schema.Infer(`{"item": {"a": 1}, "items": [{"b": true}]}`), "Root")
+
+	if !strings.Contains(code, "type Item struct") || !strings.Contains(code, "type Item2 struct") {
+		t.Errorf("Expected distinct Item and Item2 types, got:\n%s", code)
+	}
+}
+
+func TestNames(t *testing.T) {
+	tests := []struct {
+		key, goName, pyName string
+	}{
+		{"userName", "UserName", "user_name"},
+		{"user_id", "UserID", "user_id"},
+		{"HTTPStatus", "HTTPStatus", "http_status"},
+		{"content-type", "ContentType", "content_type"},
+		{"class", "Class", "class_"},
+		{"2fa", "Field2fa", "field_2fa"},
+		{"$$", "Field", "field"},
+	}
+
+	for _, tt := range tests {
+		if got := goFieldName(tt.key); got != tt.goName {
+			t.Errorf("Expected Go name %q for %q, got %q", tt.goName, tt.key, got)
+		}
+		if got := pythonFieldName(tt.key); got != tt.pyName {
+			t.Errorf("Expected Python name %q for %q, got %q", tt.pyName, tt.key, got)
+		}
+	}
+}
+
+func TestSingular(t *testing.T) {
+	tests := map[string]string{
+		"Users":      "User",
+		"Categories": "Category",
+		"Addresses":  "Address",
+		"Boxes":      "Box",
+		"Status":     "StatusItem",
+		"Data":       "DataItem",
+		"Root":       "RootItem",
+	}
+
+	for name, expected := range tests {
+		if got := singular(name); got != expected {
+			t.Errorf("Expected singular of %q to be %q, got %q", name, expected, got)
+		}
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	for name, expected := range map[string]Language{"go": Go, "TypeScript": TypeScript, "ts": TypeScript, "python": Python} {
+		lang, err := ParseLanguage(name)
+		if err != nil || lang != expected {
+			t.Errorf("Expected %v for %q, got %v (%v)", expected, name, lang, err)
+		}
+	}
+
+	if _, err := ParseLanguage("cobol"); err == nil {
+		t.Error("Expected error for unknown language, got nil")
+	}
+}
@@ -0,0 +1,120 @@
+package codegen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gataky/dive/internal/schema"
+)
+
+// generateGo emits a Go struct for every object shape. Optional and nullable
+// fields become pointers, optional fields are tagged omitempty.
+func generateGo(root *schema.Node, ts *typeSet) string {
+	var b strings.Builder
+
+	if single(root) != schema.Object {
+		fmt.Fprintf(&b, "type %s %s\n", ts.rootName, goType(root, ts))
+	}
+
+	for _, t := range ts.types {
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+		writeGoStruct(&b, t, ts)
+	}
+
+	return b.String()
+}
+
+// writeGoStruct writes a struct definition with aligned fields and tags
+func writeGoStruct(b *strings.Builder, t namedType, ts *typeSet) {
+	type field struct{ name, typ, tag string }
+
+	used := make(map[string]bool)
+	fields := make([]field, 0, len(t.node.Properties))
+	nameWidth, typeWidth := 0, 0
+
+	for _, p := range t.node.Properties {
+		required := p.Required(t.node)
+		typ := goType(p.Node, ts)
+		if (!required || nullable(p.Node)) && pointerable(typ) {
+			typ = "*" + typ
+		}
+
+		tag := p.Name
+		if !required {
+			tag += ",omitempty"
+		}
+
+		f := field{
+			name: uniqueName(goFieldName(p.Name), used),
+			typ:  typ,
+			tag:  structTag(tag),
+		}
+		nameWidth = max(nameWidth, len(f.name))
+		typeWidth = max(typeWidth, len(f.typ))
+		fields = append(fields, f)
+	}
+
+	if len(fields) == 0 {
+		fmt.Fprintf(b, "type %s struct{}\n", t.name)
+		return
+	}
+
+	fmt.Fprintf(b, "type %s struct {\n", t.name)
+	for _, f := range fields {
+		fmt.Fprintf(b, "\t%-*s %-*s %s\n", nameWidth, f.name, typeWidth, f.typ, f.tag)
+	}
+	b.WriteString("}\n")
+}
+
+// goType returns the Go type of a node, using any for unions and unknown values
+func goType(node *schema.Node, ts *typeSet) string {
+	if node == nil {
+		return "any"
+	}
+
+	switch single(node) {
+	case schema.Boolean:
+		return "bool"
+	case schema.Integer:
+		return "int64"
+	case schema.Number:
+		return "float64"
+	case schema.String:
+		return "string"
+	case schema.Array:
+		return "[]" + goType(node.Items, ts)
+	case schema.Object:
+		return ts.nameOf(node)
+	}
+	return "any"
+}
+
+// pointerable reports whether a pointer adds information to the type; slices
+// and interfaces can already be nil
+func pointerable(typ string) bool {
+	return typ != "any" && !strings.HasPrefix(typ, "[]")
+}
+
+// goFieldName converts a JSON key into an exported Go field name
+func goFieldName(key string) string {
+	name := pascalCase(key, true)
+	if name == "" {
+		return "Field"
+	}
+	if name[0] >= '0' && name[0] <= '9' {
+		return "Field" + name
+	}
+	return name
+}
+
+// structTag returns a struct tag literal for the json tag value
+func structTag(value string) string {
+	tag := "json:" + strconv.Quote(value)
+	if strings.Contains(tag, "`") {
+		return strconv.Quote(tag)
+	}
+	return "`" + tag + "`"
+}
@@ -0,0 +1,156 @@
+package codegen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gataky/dive/internal/schema"
+)
+
+// pythonKeywords cannot be used as field names
+var pythonKeywords = map[string]bool{
+	"False": true, "None": true, "True": true, "and": true, "as": true, "assert": true,
+	"async": true, "await": true, "break": true, "class": true, "continue": true,
+	"def": true, "del": true, "elif": true, "else": true, "except": true, "finally": true,
+	"for": true, "from": true, "global": true, "if": true, "import": true, "in": true,
+	"is": true, "lambda": true, "nonlocal": true, "not": true, "or": true, "pass": true,
+	"raise": true, "return": true, "try": true, "while": true, "with": true, "yield": true,
+}
+
+// pythonWriter tracks the typing names used while emitting dataclasses
+type pythonWriter struct {
+	ts      *typeSet
+	imports map[string]bool
+}
+
+// generatePython emits a dataclass for every object shape, nested classes
+// first. Optional fields default to None and follow the required ones.
+func generatePython(root *schema.Node, ts *typeSet) string {
+	w := &pythonWriter{ts: ts, imports: make(map[string]bool)}
+
+	var body strings.Builder
+	for i := len(ts.types) - 1; i >= 0; i-- {
+		body.WriteString("\n\n")
+		w.writeClass(&body, ts.types[i])
+	}
+	if single(root) != schema.Object {
+		body.WriteString("\n\n")
+		fmt.Fprintf(&body, "%s = %s\n", ts.rootName, w.pyType(root))
+	}
+
+	var b strings.Builder
+	b.WriteString("from __future__ import annotations\n")
+	if len(ts.types) > 0 {
+		b.WriteString("\nfrom dataclasses import dataclass\n")
+	}
+
+	var typing []string
+	for _, name := range []string{"Any", "Optional", "Union"} {
+		if w.imports[name] {
+			typing = append(typing, name)
+		}
+	}
+	if len(typing) > 0 {
+		if len(ts.types) == 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "from typing import %s\n", strings.Join(typing, ", "))
+	}
+
+	b.WriteString(body.String())
+	return b.String()
+}
+
+// writeClass writes a dataclass, listing required fields before optional ones
+// as dataclasses require
+func (w *pythonWriter) writeClass(b *strings.Builder, t namedType) {
+	fmt.Fprintf(b, "@dataclass\nclass %s:\n", t.name)
+	if len(t.node.Properties) == 0 {
+		b.WriteString("    pass\n")
+		return
+	}
+
+	used := make(map[string]bool)
+	names := make(map[*schema.Property]string, len(t.node.Properties))
+	for _, p := range t.node.Properties {
+		names[p] = uniqueName(pythonFieldName(p.Name), used)
+	}
+
+	for _, required := range []bool{true, false} {
+		for _, p := range t.node.Properties {
+			if p.Required(t.node) != required {
+				continue
+			}
+
+			typ := w.pyType(p.Node)
+			line := fmt.Sprintf("    %s: %s", names[p], typ)
+			if !required {
+				if !strings.HasPrefix(typ, "Optional[") && typ != "None" && typ != "Any" {
+					w.imports["Optional"] = true
+					line = fmt.Sprintf("    %s: Optional[%s]", names[p], typ)
+				}
+				line += " = None"
+			}
+			if names[p] != p.Name {
+				// Keep the original key visible when it is not a valid identifier
+				line += "  # " + strconv.Quote(p.Name)
+			}
+			b.WriteString(line + "\n")
+		}
+	}
+}
+
+// pyType returns the type annotation of a node
+func (w *pythonWriter) pyType(node *schema.Node) string {
+	if node == nil || node.Types == 0 {
+		w.imports["Any"] = true
+		return "Any"
+	}
+	if node.Types == schema.Null {
+		return "None"
+	}
+
+	var members []string
+	for _, name := range node.Types.Names() {
+		switch name {
+		case "object":
+			members = append(members, w.ts.nameOf(node))
+		case "array":
+			members = append(members, "list["+w.pyType(node.Items)+"]")
+		case "string":
+			members = append(members, "str")
+		case "integer":
+			members = append(members, "int")
+		case "number":
+			members = append(members, "float")
+		case "boolean":
+			members = append(members, "bool")
+		}
+	}
+
+	typ := members[0]
+	if len(members) > 1 {
+		w.imports["Union"] = true
+		typ = "Union[" + strings.Join(members, ", ") + "]"
+	}
+	if nullable(node) {
+		w.imports["Optional"] = true
+		typ = "Optional[" + typ + "]"
+	}
+	return typ
+}
+
+// pythonFieldName converts a JSON key into a snake_case identifier
+func pythonFieldName(key string) string {
+	name := snakeCase(key)
+	switch {
+	case name == "":
+		return "field"
+	case name[0] >= '0' && name[0] <= '9':
+		return "field_" + name
+	case pythonKeywords[name]:
+		return name + "_"
+	}
+	return name
+}
@@ -0,0 +1,70 @@
+package codegen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gataky/dive/internal/schema"
+)
+
+// tsIdentifier matches property names that do not need quoting
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// generateTypeScript emits an interface for every object shape. Optional
+// properties are marked with ?, nullable ones include null in their type.
+func generateTypeScript(root *schema.Node, ts *typeSet) string {
+	var b strings.Builder
+
+	if single(root) != schema.Object {
+		fmt.Fprintf(&b, "export type %s = %s;\n", ts.rootName, tsType(root, ts))
+	}
+
+	for _, t := range ts.types {
+		if b.Len() > 0 {
+			b.WriteByte('\n')
+		}
+
+		fmt.Fprintf(&b, "export interface %s {\n", t.name)
+		for _, p := range t.node.Properties {
+			name := p.Name
+			if !tsIdentifier.MatchString(name) {
+				name = strconv.Quote(name)
+			}
+			if !p.Required(t.node) {
+				name += "?"
+			}
+			fmt.Fprintf(&b, "  %s: %s;\n", name, tsType(p.Node, ts))
+		}
+		b.WriteString("}\n")
+	}
+
+	return b.String()
+}
+
+// tsType returns the TypeScript type of a node as a union of the observed types
+func tsType(node *schema.Node, ts *typeSet) string {
+	if node == nil || node.Types == 0 {
+		return "unknown"
+	}
+
+	var members []string
+	for _, name := range node.Types.Names() {
+		switch name {
+		case "object":
+			members = append(members, ts.nameOf(node))
+		case "array":
+			items := tsType(node.Items, ts)
+			if strings.Contains(items, " | ") {
+				items = "(" + items + ")"
+			}
+			members = append(members, items+"[]")
+		case "integer", "number":
+			members = append(members, "number")
+		default:
+			members = append(members, name)
+		}
+	}
+	return strings.Join(members, " | ")
+}
@@ -0,0 +1,214 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/gataky/dive/internal/codegen"
+	"github.com/gataky/dive/internal/export"
+	"github.com/gataky/dive/internal/ui/theme"
+)
+
+// DefaultPluginTimeout bounds how long a plugin modifier may run unless configured otherwise
+const DefaultPluginTimeout = 2 * time.Second
+
+// DefaultPipeTimeout bounds how long a command the result is piped through may run
+const DefaultPipeTimeout = 30 * time.Second
+
+// modifierName matches valid plugin modifier names, which are used after @ in paths
+var modifierName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Config holds the settings read from the configuration file
+type Config struct {
+	Theme    map[string]string   `toml:"theme"` // Theme colors by name, overriding the default theme
+	Keys     map[string][]string `toml:"keys"`  // Keys bound to each action, replacing its default keys
+	Defaults Defaults            `toml:"defaults"`
+	Plugins  Plugins             `toml:"plugins"`
+}
+
+// Defaults holds the initial state of the application
+type Defaults struct {
+	Theme            string        `toml:"theme"`             // Built-in theme; empty selects dark, or monochrome if NO_COLOR is set
+	Language         string        `toml:"language"`          // Language of the code preview
+	Wrap             bool          `toml:"wrap"`              // Whether long lines in the output wrap
+	Indent           int           `toml:"indent"`            // Spaces per level of pretty-printed JSON
+	Clipboard        string        `toml:"clipboard"`         // Clipboard backend
+	ClipboardCommand []string      `toml:"clipboard_command"` // Command used by the command clipboard backend
+	SaveFilename     string        `toml:"save_filename"`     // Filename suggested when saving output
+	DropdownHeight   int           `toml:"dropdown_height"`   // Rows of the autocomplete dropdown
+	Vim              bool          `toml:"vim"`               // Whether vim keys are bound by default
+	Mouse            bool          `toml:"mouse"`             // Whether the mouse is enabled
+	PipeTimeout      time.Duration `toml:"pipe_timeout"`      // How long a command the result is piped through may run
+	ExpandStrings    bool          `toml:"expand_strings"`    // Whether paths traverse strings holding JSON
+}
+
+// Plugins configures user-defined modifiers. Because they run external
+// commands, they are only registered when explicitly enabled.
+type Plugins struct {
+	Enabled   bool                      `toml:"enabled"`
+	Timeout   time.Duration             `toml:"timeout"`
+	Modifiers map[string]PluginModifier `toml:"modifiers"`
+}
+
+// PluginModifier defines a modifier that transforms the current value either
+// with an external command or with an embedded expression
+type PluginModifier struct {
+	Description string        `toml:"description"`
+	Command     []string      `toml:"command"`    // Executable and arguments; the value is written to stdin
+	Expression  string        `toml:"expression"` // Expression evaluated with value and arg
+	Timeout     time.Duration `toml:"timeout"`    // Overrides the plugins timeout
+}
+
+// Default returns the configuration used when no configuration file exists
+func Default() *Config {
+	return &Config{
+		Theme: map[string]string{},
+		Keys:  map[string][]string{},
+		Defaults: Defaults{
+			Language:       "go",
+			Wrap:           true,
+			Indent:         2,
+			Clipboard:      export.ClipboardAuto,
+			SaveFilename:   "output.json",
+			DropdownHeight: 8,
+			Mouse:          true,
+			PipeTimeout:    DefaultPipeTimeout,
+		},
+		Plugins: Plugins{
+			Timeout:   DefaultPluginTimeout,
+			Modifiers: map[string]PluginModifier{},
+		},
+	}
+}
+
+// DefaultPath returns the location of the configuration file: $DIVE_CONFIG if
+// set, otherwise following the XDG base directory specification
+// $XDG_CONFIG_HOME/dive/config.toml, or ~/.config/dive/config.toml
+func DefaultPath() (string, error) {
+	if path := os.Getenv("DIVE_CONFIG"); path != "" {
+		return path, nil
+	}
+
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "dive", "config.toml"), nil
+}
+
+// Load reads the configuration file at path on top of the defaults. A missing
+// file is not an error; unknown or invalid settings are.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	meta, err := toml.DecodeFile(path, cfg)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
+		return nil, fmt.Errorf("invalid config %s: unknown setting %s", path, strings.Join(keys, ", "))
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Validate reports the first setting that cannot be used. Key bindings are
+// checked by the UI, which knows the available actions.
+func (c *Config) Validate() error {
+	colors := theme.DefaultTheme()
+	for _, name := range sortedKeys(c.Theme) {
+		if err := colors.Set(name, c.Theme[name]); err != nil {
+			return fmt.Errorf("theme.%s: %w", name, err)
+		}
+	}
+
+	d := c.Defaults
+	if d.Theme != "" {
+		if _, err := theme.Named(d.Theme); err != nil {
+			return fmt.Errorf("defaults.theme: %w", err)
+		}
+	}
+	if _, err := codegen.ParseLanguage(d.Language); err != nil {
+		return fmt.Errorf("defaults.language: %w", err)
+	}
+	if d.Indent < 0 || d.Indent > 8 {
+		return fmt.Errorf("defaults.indent must be between 0 and 8")
+	}
+	switch d.Clipboard {
+	case export.ClipboardAuto, export.ClipboardOSC52:
+	case export.ClipboardCommand:
+		if len(d.ClipboardCommand) == 0 {
+			return fmt.Errorf("defaults.clipboard_command is required for the command clipboard")
+		}
+	default:
+		return fmt.Errorf("defaults.clipboard must be %s, %s or %s", export.ClipboardAuto, export.ClipboardOSC52, export.ClipboardCommand)
+	}
+	if d.SaveFilename == "" {
+		return fmt.Errorf("defaults.save_filename cannot be empty")
+	}
+	if d.DropdownHeight < 3 || d.DropdownHeight > 30 {
+		return fmt.Errorf("defaults.dropdown_height must be between 3 and 30")
+	}
+
+	if d.PipeTimeout <= 0 {
+		return fmt.Errorf("defaults.pipe_timeout must be positive")
+	}
+
+	if c.Plugins.Timeout <= 0 {
+		return fmt.Errorf("plugins.timeout must be positive")
+	}
+
+	for _, name := range c.PluginNames() {
+		m := c.Plugins.Modifiers[name]
+		switch {
+		case !modifierName.MatchString(name):
+			return fmt.Errorf("plugins.modifiers.%s: name must contain only letters, digits and underscores", name)
+		case len(m.Command) == 0 && m.Expression == "":
+			return fmt.Errorf("plugins.modifiers.%s: either command or expression is required", name)
+		case len(m.Command) > 0 && m.Expression != "":
+			return fmt.Errorf("plugins.modifiers.%s: command and expression cannot both be set", name)
+		case m.Timeout < 0:
+			return fmt.Errorf("plugins.modifiers.%s: timeout must be positive", name)
+		}
+	}
+
+	return nil
+}
+
+// PluginNames returns the names of the configured plugin modifiers in sorted order
+func (c *Config) PluginNames() []string {
+	return sortedKeys(c.Plugins.Modifiers)
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig writes content to a config file in a temporary directory
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "missing.toml"))
+	if err != nil {
+		t.Fatalf("Expected no error for a missing file, got: %v", err)
+	}
+	if cfg.Plugins.Enabled || cfg.Plugins.Timeout != DefaultPluginTimeout {
+		t.Errorf("Expected default plugin settings, got %+v", cfg.Plugins)
+	}
+}
+
+func TestLoadPlugins(t *testing.T) {
+	path := writeConfig(t, `
+[plugins]
+enabled = true
+timeout = "5s"
+
+[plugins.modifiers.resolve]
+description = "Resolve user IDs"
+command = ["resolve-ids", "--table", "users.csv"]
+timeout = "500ms"
+
+[plugins.modifiers.upper]
+expression = "upper(value)"
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !cfg.Plugins.Enabled || cfg.Plugins.Timeout != 5*time.Second {
+		t.Errorf("Expected enabled plugins with 5s timeout, got %+v", cfg.Plugins)
+	}
+
+	resolve := cfg.Plugins.Modifiers["resolve"]
+	if len(resolve.Command) != 3 || resolve.Timeout != 500*time.Millisecond || resolve.Description != "Resolve user IDs" {
+		t.Errorf("Unexpected resolve plugin: %+v", resolve)
+	}
+
+	names := cfg.PluginNames()
+	if len(names) != 2 || names[0] != "resolve" || names[1] != "upper" {
+		t.Errorf("Expected sorted plugin names [resolve upper], got %v", names)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"syntax", "[plugins", "invalid config"},
+		{"unknown key", "[plugins]\nenable = true", "unknown setting plugins.enable"},
+		{"no command", "[plugins.modifiers.x]\ndescription = \"x\"", "either command or expression is required"},
+		{"both", "[plugins.modifiers.x]\ncommand = [\"cat\"]\nexpression = \"value\"", "cannot both be set"},
+		{"bad name", "[plugins.modifiers.\"a-b\"]\ncommand = [\"cat\"]", "name must contain only letters"},
+		{"bad timeout", "[plugins]\ntimeout = \"0s\"", "plugins.timeout must be positive"},
+		{"bad color", "[theme]\nborder_focused = \"blu\"", `theme.border_focused: invalid color "blu"`},
+		{"unknown color", "[theme]\nborder = \"red\"", `unknown theme color "border"`},
+		{"bad theme", "[defaults]\ntheme = \"neon\"", `defaults.theme: unknown theme "neon"`},
+		{"bad language", "[defaults]\nlanguage = \"rust\"", "defaults.language"},
+		{"bad indent", "[defaults]\nindent = 10", "defaults.indent must be between 0 and 8"},
+		{"bad clipboard", "[defaults]\nclipboard = \"x11\"", "defaults.clipboard must be auto, osc52 or command"},
+		{"no clipboard command", "[defaults]\nclipboard = \"command\"", "defaults.clipboard_command is required"},
+		{"bad dropdown", "[defaults]\ndropdown_height = 1", "defaults.dropdown_height must be between 3 and 30"},
+		{"bad pipe timeout", "[defaults]\npipe_timeout = \"-1s\"", "defaults.pipe_timeout must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load(writeConfig(t, tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestLoadThemeAndDefaults(t *testing.T) {
+	path := writeConfig(t, `
+[theme]
+border_focused = "#ff8800"
+
+[keys]
+quit = ["ctrl+x", "f10"]
+
+[defaults]
+language = "python"
+wrap = false
+indent = 4
+vim = true
+mouse = false
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if cfg.Theme["border_focused"] != "#ff8800" {
+		t.Errorf("Expected border_focused #ff8800, got %q", cfg.Theme["border_focused"])
+	}
+	if len(cfg.Keys["quit"]) != 2 {
+		t.Errorf("Expected two quit keys, got %v", cfg.Keys["quit"])
+	}
+	d := cfg.Defaults
+	if d.Language != "python" || d.Wrap || d.Indent != 4 || !d.Vim || d.Mouse {
+		t.Errorf("Unexpected defaults: %+v", d)
+	}
+	// Settings that are not in the file keep their defaults
+	if d.SaveFilename != "output.json" || d.DropdownHeight != 8 || d.Clipboard != "auto" {
+		t.Errorf("Expected unset defaults to be kept, got %+v", d)
+	}
+}
+
+func TestWriteRoundTrip(t *testing.T) {
+	cfg := Default()
+	cfg.Keys = map[string][]string{"quit": {"ctrl+q"}, "help": {}}
+	cfg.Theme["text_accent"] = "#123456"
+	cfg.Defaults.ClipboardCommand = []string{"wl-copy"}
+	cfg.Defaults.Theme = "solarized"
+	cfg.Defaults.Vim = true
+	cfg.Defaults.Mouse = false
+	cfg.Defaults.PipeTimeout = 5 * time.Second
+	cfg.Defaults.ExpandStrings = true
+	cfg.Plugins.Modifiers["upper"] = PluginModifier{Description: `Say "hi"`, Expression: "upper(value)"}
+
+	var b strings.Builder
+	if err := cfg.Write(&b); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	loaded, err := Load(writeConfig(t, b.String()))
+	if err != nil {
+		t.Fatalf("Expected written config to load, got: %v\n%s", err, b.String())
+	}
+
+	if len(loaded.Theme) != 1 || loaded.Theme["text_accent"] != "#123456" {
+		t.Errorf("Expected only the configured theme color to round trip, got %v", loaded.Theme)
+	}
+	if len(loaded.Keys["quit"]) != 1 || len(loaded.Keys["help"]) != 0 {
+		t.Errorf("Expected keys to round trip, got %v", loaded.Keys)
+	}
+	if !reflect.DeepEqual(loaded.Defaults, cfg.Defaults) {
+		t.Errorf("Expected defaults to round trip, got %+v", loaded.Defaults)
+	}
+	if loaded.Plugins.Modifiers["upper"].Description != `Say "hi"` {
+		t.Errorf("Expected plugin to round trip, got %+v", loaded.Plugins.Modifiers)
+	}
+}
+
+func TestDefaultPathOverride(t *testing.T) {
+	t.Setenv("DIVE_CONFIG", "/tmp/dive.toml")
+
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if path != "/tmp/dive.toml" {
+		t.Errorf("Expected DIVE_CONFIG path, got %s", path)
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("DIVE_CONFIG", "")
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/xdg")
+
+	path, err := DefaultPath()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if path != filepath.Join("/tmp/xdg", "dive", "config.toml") {
+		t.Errorf("Expected path under XDG_CONFIG_HOME, got %s", path)
+	}
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gataky/dive/internal/ui/theme"
+)
+
+// Write writes the configuration as a commented TOML file that Load accepts.
+// Theme colors that are not set are written as comments with the colors of
+// the dark theme.
+func (c *Config) Write(w io.Writer) error {
+	b := bufio.NewWriter(w)
+
+	fmt.Fprintln(b, "# dive configuration, read from $DIVE_CONFIG, $XDG_CONFIG_HOME/dive/config.toml")
+	fmt.Fprintln(b, "# or ~/.config/dive/config.toml")
+
+	d := c.Defaults
+	fmt.Fprintln(b)
+	fmt.Fprintln(b, "[defaults]")
+	fmt.Fprintf(b, "theme = %s # %s; empty uses dark, or monochrome if NO_COLOR is set\n", quote(d.Theme), strings.Join(theme.Names, ", "))
+	fmt.Fprintf(b, "language = %s # Code preview language: go, typescript or python\n", quote(d.Language))
+	fmt.Fprintf(b, "wrap = %t # Wrap long lines in the output\n", d.Wrap)
+	fmt.Fprintf(b, "indent = %d # Spaces per level of pretty-printed JSON\n", d.Indent)
+	fmt.Fprintf(b, "clipboard = %s # auto, osc52 (works over SSH) or command\n", quote(d.Clipboard))
+	fmt.Fprintf(b, "clipboard_command = %s # e.g. [\"wl-copy\"], receives the content on stdin\n", quoteList(d.ClipboardCommand))
+	fmt.Fprintf(b, "save_filename = %s\n", quote(d.SaveFilename))
+	fmt.Fprintf(b, "dropdown_height = %d\n", d.DropdownHeight)
+	fmt.Fprintf(b, "vim = %t # Bind vim keys such as j, k, gg, yy and : in the output panel\n", d.Vim)
+	fmt.Fprintf(b, "mouse = %t # Click keys, suggestions and hints, scroll and drag the split divider\n", d.Mouse)
+	fmt.Fprintf(b, "pipe_timeout = %s # How long a command the result is piped through with | may run\n", quote(d.PipeTimeout.String()))
+	fmt.Fprintf(b, "expand_strings = %t # Let paths traverse strings that hold JSON, as if they were parsed\n", d.ExpandStrings)
+
+	fmt.Fprintln(b)
+	fmt.Fprintln(b, "# Colors override those of every theme. They are W3C color names, #rrggbb")
+	fmt.Fprintln(b, "# or default for the terminal's color.")
+	fmt.Fprintln(b, "[theme]")
+	for _, field := range theme.DefaultTheme().Fields() {
+		if value, ok := c.Theme[field.Name]; ok {
+			fmt.Fprintf(b, "%s = %s\n", field.Name, quote(value))
+		} else {
+			fmt.Fprintf(b, "# %s = %s\n", field.Name, quote(theme.ColorName(*field.Color)))
+		}
+	}
+
+	fmt.Fprintln(b)
+	fmt.Fprintln(b, "# Keys such as ctrl+q, alt+left, shift+tab, f1 or sequences like \"g g\"; [] unbinds an action")
+	fmt.Fprintln(b, "[keys]")
+	for _, action := range sortedKeys(c.Keys) {
+		fmt.Fprintf(b, "%s = %s\n", action, quoteList(c.Keys[action]))
+	}
+
+	fmt.Fprintln(b)
+	fmt.Fprintln(b, "# Plugin modifiers run external commands and are only registered when enabled")
+	fmt.Fprintln(b, "[plugins]")
+	fmt.Fprintf(b, "enabled = %t\n", c.Plugins.Enabled)
+	fmt.Fprintf(b, "timeout = %s\n", quote(c.Plugins.Timeout.String()))
+	if len(c.Plugins.Modifiers) == 0 {
+		fmt.Fprintln(b)
+		fmt.Fprintln(b, "# [plugins.modifiers.upper]")
+		fmt.Fprintln(b, "# description = \"Uppercase a string\"")
+		fmt.Fprintln(b, "# expression = \"upper(value)\"")
+	}
+	for _, name := range c.PluginNames() {
+		m := c.Plugins.Modifiers[name]
+		fmt.Fprintln(b)
+		fmt.Fprintf(b, "[plugins.modifiers.%s]\n", name)
+		if m.Description != "" {
+			fmt.Fprintf(b, "description = %s\n", quote(m.Description))
+		}
+		if len(m.Command) > 0 {
+			fmt.Fprintf(b, "command = %s\n", quoteList(m.Command))
+		}
+		if m.Expression != "" {
+			fmt.Fprintf(b, "expression = %s\n", quote(m.Expression))
+		}
+		if m.Timeout != 0 {
+			fmt.Fprintf(b, "timeout = %s\n", quote(m.Timeout.String()))
+		}
+	}
+
+	return b.Flush()
+}
+
+// quote encodes a string as a TOML basic string, which accepts the escapes JSON uses
+func quote(s string) string {
+	encoded, _ := json.Marshal(s)
+	return string(encoded)
+}
+
+// quoteList encodes strings as a TOML array
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = quote(value)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
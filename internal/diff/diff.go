@@ -0,0 +1,264 @@
+package diff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// ChangeType describes how a value differs between two documents
+type ChangeType int
+
+const (
+	Added   ChangeType = iota // Value only exists in the new document
+	Removed                   // Value only exists in the old document
+	Changed                   // Value exists in both documents but differs
+)
+
+// String returns the single character marker used when displaying a change
+func (t ChangeType) String() string {
+	switch t {
+	case Added:
+		return "+"
+	case Removed:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+// Change represents a single difference between two JSON documents
+type Change struct {
+	Type    ChangeType
+	Path    string // gjson path of the value, using indices of the document it exists in
+	Pointer string // RFC 6901 JSON pointer, valid when patch operations are applied in order
+	OldRaw  string // Raw JSON in the old document (empty for Added)
+	NewRaw  string // Raw JSON in the new document (empty for Removed)
+}
+
+// Options controls how documents are compared
+type Options struct {
+	// ArrayKey matches array elements by the value of this object field instead
+	// of by index. Arrays where any element lacks the field fall back to index matching.
+	ArrayKey string
+}
+
+// Compare returns the structural differences between two JSON documents.
+// Object key order is ignored. Changes are returned in an order in which their
+// JSON pointers can be applied as a patch.
+func Compare(oldJSON, newJSON string, opts Options) ([]Change, error) {
+	if !gjson.Valid(oldJSON) {
+		return nil, fmt.Errorf("invalid JSON in old document")
+	}
+	if !gjson.Valid(newJSON) {
+		return nil, fmt.Errorf("invalid JSON in new document")
+	}
+
+	w := &walker{opts: opts}
+	w.compare(gjson.Parse(oldJSON), gjson.Parse(newJSON), nil, nil)
+	return w.changes, nil
+}
+
+// walker accumulates changes while recursively comparing two values
+type walker struct {
+	opts    Options
+	changes []Change
+}
+
+// compare records the differences between a and b found at the given path.
+// path holds gjson path segments and pointer holds JSON pointer segments.
+func (w *walker) compare(a, b gjson.Result, path, pointer []string) {
+	switch {
+	case a.IsObject() && b.IsObject():
+		w.compareObjects(a, b, path, pointer)
+	case a.IsArray() && b.IsArray():
+		if w.opts.ArrayKey != "" && allHaveKey(a, w.opts.ArrayKey) && allHaveKey(b, w.opts.ArrayKey) {
+			w.compareArraysByKey(a, b, path, pointer)
+		} else {
+			w.compareArraysByIndex(a, b, path, pointer)
+		}
+	default:
+		if !Equal(a, b) {
+			w.add(Changed, path, pointer, a.Raw, b.Raw)
+		}
+	}
+}
+
+// compareObjects compares two objects key by key, ignoring key order
+func (w *walker) compareObjects(a, b gjson.Result, path, pointer []string) {
+	newValues := b.Map()
+
+	a.ForEach(func(key, value gjson.Result) bool {
+		k := key.String()
+		childPath := appendSegment(path, gjson.Escape(k))
+		childPointer := appendSegment(pointer, escapePointer(k))
+		if other, ok := newValues[k]; ok {
+			w.compare(value, other, childPath, childPointer)
+		} else {
+			w.add(Removed, childPath, childPointer, value.Raw, "")
+		}
+		return true
+	})
+
+	oldValues := a.Map()
+	b.ForEach(func(key, value gjson.Result) bool {
+		k := key.String()
+		if _, ok := oldValues[k]; !ok {
+			w.add(Added, appendSegment(path, gjson.Escape(k)), appendSegment(pointer, escapePointer(k)), "", value.Raw)
+		}
+		return true
+	})
+}
+
+// compareArraysByIndex compares elements at the same position. Removals are
+// recorded from the highest index down so that patch pointers stay valid.
+func (w *walker) compareArraysByIndex(a, b gjson.Result, path, pointer []string) {
+	oldItems := a.Array()
+	newItems := b.Array()
+
+	common := min(len(oldItems), len(newItems))
+	for i := 0; i < common; i++ {
+		idx := strconv.Itoa(i)
+		w.compare(oldItems[i], newItems[i], appendSegment(path, idx), appendSegment(pointer, idx))
+	}
+
+	for i := len(oldItems) - 1; i >= common; i-- {
+		idx := strconv.Itoa(i)
+		w.add(Removed, appendSegment(path, idx), appendSegment(pointer, idx), oldItems[i].Raw, "")
+	}
+
+	for i := common; i < len(newItems); i++ {
+		idx := strconv.Itoa(i)
+		w.add(Added, appendSegment(path, idx), appendSegment(pointer, idx), "", newItems[i].Raw)
+	}
+}
+
+// compareArraysByKey matches elements by the value of the configured key field.
+// Matched elements are compared first, unmatched old elements are removed from
+// the highest index down and unmatched new elements are appended.
+func (w *walker) compareArraysByKey(a, b gjson.Result, path, pointer []string) {
+	oldItems := a.Array()
+	newItems := b.Array()
+
+	newIndex := make(map[string]int, len(newItems))
+	for i, item := range newItems {
+		newIndex[keyOf(item, w.opts.ArrayKey)] = i
+	}
+
+	matched := make(map[int]bool, len(newItems))
+	var removed []int
+	for i, item := range oldItems {
+		j, ok := newIndex[keyOf(item, w.opts.ArrayKey)]
+		if !ok || matched[j] {
+			removed = append(removed, i)
+			continue
+		}
+		matched[j] = true
+		idx := strconv.Itoa(i)
+		w.compare(item, newItems[j], appendSegment(path, idx), appendSegment(pointer, idx))
+	}
+
+	for k := len(removed) - 1; k >= 0; k-- {
+		idx := strconv.Itoa(removed[k])
+		w.add(Removed, appendSegment(path, idx), appendSegment(pointer, idx), oldItems[removed[k]].Raw, "")
+	}
+
+	for j, item := range newItems {
+		if !matched[j] {
+			w.add(Added, appendSegment(path, strconv.Itoa(j)), appendSegment(pointer, "-"), "", item.Raw)
+		}
+	}
+}
+
+// add records a change at the given path
+func (w *walker) add(t ChangeType, path, pointer []string, oldRaw, newRaw string) {
+	w.changes = append(w.changes, Change{
+		Type:    t,
+		Path:    strings.Join(path, "."),
+		Pointer: joinPointer(pointer),
+		OldRaw:  oldRaw,
+		NewRaw:  newRaw,
+	})
+}
+
+// Equal reports whether two values are structurally equal, ignoring object key order
+func Equal(a, b gjson.Result) bool {
+	if a.Type != b.Type {
+		return false
+	}
+
+	switch a.Type {
+	case gjson.Number:
+		return a.Num == b.Num
+	case gjson.String:
+		return a.Str == b.Str
+	case gjson.JSON:
+		if a.IsObject() != b.IsObject() {
+			return false
+		}
+		if a.IsArray() {
+			x, y := a.Array(), b.Array()
+			if len(x) != len(y) {
+				return false
+			}
+			for i := range x {
+				if !Equal(x[i], y[i]) {
+					return false
+				}
+			}
+			return true
+		}
+		x, y := a.Map(), b.Map()
+		if len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			if other, ok := y[k]; !ok || !Equal(v, other) {
+				return false
+			}
+		}
+		return true
+	default:
+		// Null, True and False carry no further value
+		return true
+	}
+}
+
+// allHaveKey reports whether every element of the array is an object containing key
+func allHaveKey(arr gjson.Result, key string) bool {
+	ok := true
+	arr.ForEach(func(_, item gjson.Result) bool {
+		if !item.IsObject() || !item.Get(gjson.Escape(key)).Exists() {
+			ok = false
+		}
+		return ok
+	})
+	return ok
+}
+
+// keyOf returns the raw JSON of the key field so that 1 and "1" do not match
+func keyOf(item gjson.Result, key string) string {
+	return item.Get(gjson.Escape(key)).Raw
+}
+
+// appendSegment returns a copy of path with segment appended
+func appendSegment(path []string, segment string) []string {
+	next := make([]string, len(path), len(path)+1)
+	copy(next, path)
+	return append(next, segment)
+}
+
+// escapePointer escapes a key for use as a JSON pointer reference token (RFC 6901)
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
+
+// joinPointer builds a JSON pointer from reference tokens
+func joinPointer(tokens []string) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+	return "/" + strings.Join(tokens, "/")
+}
@@ -0,0 +1,195 @@
+package diff
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestCompareIdentical(t *testing.T) {
+	changes, err := Compare(`{"a": 1, "b": [1, 2]}`, `{"b": [1, 2], "a": 1.0}`, Options{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(changes) != 0 {
+		t.Errorf("Expected no changes for key-reordered documents, got %v", changes)
+	}
+}
+
+func TestCompareObjects(t *testing.T) {
+	oldJSON := `{"name": "Alice", "age": 30, "email": "a@example.com"}`
+	newJSON := `{"name": "Alice", "age": 31, "city": "NYC"}`
+
+	changes, err := Compare(oldJSON, newJSON, Options{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []Change{
+		{Type: Changed, Path: "age", Pointer: "/age", OldRaw: "30", NewRaw: "31"},
+		{Type: Removed, Path: "email", Pointer: "/email", OldRaw: `"a@example.com"`},
+		{Type: Added, Path: "city", Pointer: "/city", NewRaw: `"NYC"`},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, changes)
+	}
+}
+
+func TestCompareEscapesKeys(t *testing.T) {
+	changes, err := Compare(`{"a.b": {"c/d": 1}}`, `{"a.b": {"c/d": 2}}`, Options{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 change, got %d", len(changes))
+	}
+	if changes[0].Path != `a\.b.c\/d` {
+		t.Errorf("Expected escaped gjson path, got %q", changes[0].Path)
+	}
+	if changes[0].Pointer != "/a.b/c~1d" {
+		t.Errorf("Expected escaped JSON pointer, got %q", changes[0].Pointer)
+	}
+}
+
+func TestCompareArraysByIndex(t *testing.T) {
+	changes, err := Compare(`{"items": [1, 2, 3, 4]}`, `{"items": [1, 5]}`, Options{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var pointers []string
+	for _, c := range changes {
+		pointers = append(pointers, c.Type.String()+c.Pointer)
+	}
+
+	// Removals must come from the end so that later indices stay valid
+	expected := []string{"~/items/1", "-/items/3", "-/items/2"}
+	if !reflect.DeepEqual(pointers, expected) {
+		t.Errorf("Expected %v, got %v", expected, pointers)
+	}
+}
+
+func TestCompareArraysByKey(t *testing.T) {
+	oldJSON := `[{"id": 1, "v": "a"}, {"id": 2, "v": "b"}, {"id": 3, "v": "c"}]`
+	newJSON := `[{"id": 3, "v": "c"}, {"id": 1, "v": "z"}, {"id": 4, "v": "d"}]`
+
+	changes, err := Compare(oldJSON, newJSON, Options{ArrayKey: "id"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	expected := []Change{
+		{Type: Changed, Path: "0.v", Pointer: "/0/v", OldRaw: `"a"`, NewRaw: `"z"`},
+		{Type: Removed, Path: "1", Pointer: "/1", OldRaw: `{"id": 2, "v": "b"}`},
+		{Type: Added, Path: "2", Pointer: "/-", NewRaw: `{"id": 4, "v": "d"}`},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, changes)
+	}
+}
+
+func TestCompareArraysByKeyFallsBackToIndex(t *testing.T) {
+	changes, err := Compare(`[{"id": 1}, 2]`, `[2, {"id": 1}]`, Options{ArrayKey: "id"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(changes) != 2 {
+		t.Errorf("Expected index matching with 2 changes, got %+v", changes)
+	}
+}
+
+func TestCompareInvalidJSON(t *testing.T) {
+	if _, err := Compare(`{invalid`, `{}`, Options{}); err == nil {
+		t.Error("Expected error for invalid old document")
+	}
+	if _, err := Compare(`{}`, `{invalid`, Options{}); err == nil {
+		t.Error("Expected error for invalid new document")
+	}
+}
+
+func TestPatch(t *testing.T) {
+	changes, err := Compare(`{"a": 1, "b": 2}`, `{"a": 3, "c": [true]}`, Options{})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var ops []map[string]any
+	if err := json.Unmarshal([]byte(Patch(changes)), &ops); err != nil {
+		t.Fatalf("Patch is not valid JSON: %v", err)
+	}
+
+	expected := []map[string]any{
+		{"op": "replace", "path": "/a", "value": float64(3)},
+		{"op": "remove", "path": "/b"},
+		{"op": "add", "path": "/c", "value": []any{true}},
+	}
+	if !reflect.DeepEqual(ops, expected) {
+		t.Errorf("Expected %v, got %v", expected, ops)
+	}
+}
+
+func TestScope(t *testing.T) {
+	changes := []Change{{Type: Changed, Path: "port", Pointer: "/port"}}
+
+	scoped, err := Scope(changes, `envs.prod`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if scoped[0].Path != "envs.prod.port" || scoped[0].Pointer != "/envs/prod/port" {
+		t.Errorf("Unexpected scoped change: %+v", scoped[0])
+	}
+
+	scoped, err = Scope(changes, `envs.#.port`)
+	if err == nil {
+		t.Error("Expected error for path that cannot be a JSON pointer")
+	}
+	if scoped[0].Pointer != "/port" {
+		t.Errorf("Expected pointer to stay relative, got %q", scoped[0].Pointer)
+	}
+}
+
+func TestPathToPointer(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+		wantErr  bool
+	}{
+		{"", "", false},
+		{"users.0.name", "/users/0/name", false},
+		{`first\.name`, "/first.name", false},
+		{"a/b.c~d", "/a~1b/c~0d", false},
+		{"users.#.name", "", true},
+		{"items|@reverse", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			pointer, err := PathToPointer(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expected error=%v, got: %v", tt.wantErr, err)
+			}
+			if pointer != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, pointer)
+			}
+		})
+	}
+}
+
+func TestLineChanges(t *testing.T) {
+	a := []string{"{", `  "host": "staging",`, `  "port": 80`, "}"}
+	b := []string{"{", `  "debug": true,`, `  "host": "prod",`, `  "port": 80`, "}"}
+
+	aChanged, bChanged := LineChanges(a, b)
+
+	expectedA := []bool{false, true, false, false}
+	expectedB := []bool{false, true, true, false, false}
+	if !reflect.DeepEqual(aChanged, expectedA) {
+		t.Errorf("Expected %v for old lines, got %v", expectedA, aChanged)
+	}
+	if !reflect.DeepEqual(bChanged, expectedB) {
+		t.Errorf("Expected %v for new lines, got %v", expectedB, bChanged)
+	}
+}
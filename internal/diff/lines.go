@@ -0,0 +1,60 @@
+package diff
+
+// maxLineDiffCells bounds the size of the LCS table. Larger inputs fall back to
+// comparing lines at the same position.
+const maxLineDiffCells = 4_000_000
+
+// LineChanges reports which lines of a and b are not part of their longest
+// common subsequence, i.e. the lines that differ between the two texts.
+func LineChanges(a, b []string) (aChanged, bChanged []bool) {
+	aChanged = make([]bool, len(a))
+	bChanged = make([]bool, len(b))
+
+	if len(a)*len(b) > maxLineDiffCells {
+		for i := range a {
+			aChanged[i] = i >= len(b) || a[i] != b[i]
+		}
+		for j := range b {
+			bChanged[j] = j >= len(a) || a[j] != b[j]
+		}
+		return aChanged, bChanged
+	}
+
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			aChanged[i] = true
+			i++
+		default:
+			bChanged[j] = true
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		aChanged[i] = true
+	}
+	for ; j < len(b); j++ {
+		bChanged[j] = true
+	}
+
+	return aChanged, bChanged
+}
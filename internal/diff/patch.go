@@ -0,0 +1,98 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Patch renders changes as a pretty-printed RFC 6902 JSON Patch document
+func Patch(changes []Change) string {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, c := range changes {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		path, _ := json.Marshal(c.Pointer)
+		switch c.Type {
+		case Added:
+			fmt.Fprintf(&buf, `{"op":"add","path":%s,"value":%s}`, path, c.NewRaw)
+		case Removed:
+			fmt.Fprintf(&buf, `{"op":"remove","path":%s}`, path)
+		case Changed:
+			fmt.Fprintf(&buf, `{"op":"replace","path":%s,"value":%s}`, path, c.NewRaw)
+		}
+	}
+	buf.WriteByte(']')
+
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, buf.Bytes(), "", "  "); err != nil {
+		return buf.String()
+	}
+	return pretty.String()
+}
+
+// Scope prefixes the paths of changes that were computed for the values at a
+// gjson path. Pointers can only be prefixed when the path is a plain chain of
+// keys and indices; for queries or modifiers an error is returned and the
+// pointers stay relative to the scoped value.
+func Scope(changes []Change, path string) ([]Change, error) {
+	if path == "" {
+		return changes, nil
+	}
+
+	prefix, err := PathToPointer(path)
+
+	scoped := make([]Change, len(changes))
+	for i, c := range changes {
+		scoped[i] = c
+		if c.Path == "" {
+			scoped[i].Path = path
+		} else {
+			scoped[i].Path = path + "." + c.Path
+		}
+		if err == nil {
+			scoped[i].Pointer = prefix + c.Pointer
+		}
+	}
+
+	return scoped, err
+}
+
+// PathToPointer converts a plain gjson path such as "users.0.first\.name" into
+// the JSON pointer "/users/0/first.name". Paths using wildcards, queries,
+// modifiers or multipaths cannot be converted.
+func PathToPointer(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+
+	var b strings.Builder
+	var segment strings.Builder
+	flush := func() {
+		b.WriteByte('/')
+		b.WriteString(escapePointer(segment.String()))
+		segment.Reset()
+	}
+
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		switch {
+		case c == '\\' && i+1 < len(path):
+			i++
+			segment.WriteByte(path[i])
+		case c == '.':
+			flush()
+		case strings.IndexByte("*?#@|{}[]()", c) >= 0:
+			return "", fmt.Errorf("path %q cannot be expressed as a JSON pointer", path)
+		default:
+			segment.WriteByte(c)
+		}
+	}
+	flush()
+
+	return b.String(), nil
+}
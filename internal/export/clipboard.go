@@ -1,21 +1,90 @@
 package export
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
+	"os"
+	"os/exec"
+	"strings"
 
 	"github.com/atotto/clipboard"
 )
 
-// CopyToClipboard copies the provided content to the system clipboard
+// Clipboard backends
+const (
+	ClipboardAuto    = "auto"    // The system clipboard through xclip, xsel, pbcopy or clip.exe
+	ClipboardOSC52   = "osc52"   // The terminal's clipboard escape sequence, which also works over SSH
+	ClipboardCommand = "command" // A user-defined command reading the content from stdin
+)
+
+// writeClipboard copies content with the selected backend
+var writeClipboard = clipboard.WriteAll
+
+// SetClipboard selects the clipboard backend used by CopyToClipboard. The
+// command backend runs command with the content on stdin.
+func SetClipboard(backend string, command []string) error {
+	switch backend {
+	case ClipboardAuto:
+		writeClipboard = clipboard.WriteAll
+	case ClipboardOSC52:
+		writeClipboard = writeOSC52
+	case ClipboardCommand:
+		if len(command) == 0 {
+			return fmt.Errorf("the command clipboard requires a command")
+		}
+		writeClipboard = func(content string) error {
+			return runClipboardCommand(command, content)
+		}
+	default:
+		return fmt.Errorf("unknown clipboard %q", backend)
+	}
+	return nil
+}
+
+// CopyToClipboard copies the provided content to the clipboard
 func CopyToClipboard(content string) error {
 	if content == "" {
 		return fmt.Errorf("cannot copy empty content to clipboard")
 	}
 
-	err := clipboard.WriteAll(content)
+	err := writeClipboard(content)
 	if err != nil {
 		return fmt.Errorf("failed to copy to clipboard: %w", err)
 	}
 
 	return nil
 }
+
+// osc52Sequence returns the escape sequence asking the terminal to put content on the clipboard
+func osc52Sequence(content string) string {
+	return "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(content)) + "\a"
+}
+
+// writeOSC52 sends the clipboard escape sequence to the controlling terminal
+func writeOSC52(content string) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer tty.Close()
+
+	_, err = tty.WriteString(osc52Sequence(content))
+	return err
+}
+
+// runClipboardCommand runs command with content on stdin
+func runClipboardCommand(command []string, content string) error {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdin = strings.NewReader(content)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return fmt.Errorf("%w: %s", err, message)
+		}
+		return err
+	}
+	return nil
+}
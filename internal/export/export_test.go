@@ -148,3 +148,73 @@ func TestCopyToClipboardSuccess(t *testing.T) {
 		t.Logf("Warning: clipboard test failed (may be expected in CI): %v", err)
 	}
 }
+
+func TestSetClipboardCommand(t *testing.T) {
+	defer SetClipboard(ClipboardAuto, nil)
+
+	path := filepath.Join(t.TempDir(), "clipboard.txt")
+	if err := SetClipboard(ClipboardCommand, []string{"sh", "-c", "cat > " + path}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if err := CopyToClipboard(`{"copied": true}`); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(data) != `{"copied": true}` {
+		t.Errorf("Expected the content on stdin of the command, got %q", string(data))
+	}
+}
+
+func TestSetClipboardErrors(t *testing.T) {
+	if err := SetClipboard("x11", nil); err == nil {
+		t.Error("Expected an error for an unknown clipboard")
+	}
+	if err := SetClipboard(ClipboardCommand, nil); err == nil {
+		t.Error("Expected an error for the command clipboard without a command")
+	}
+}
+
+func TestOSC52Sequence(t *testing.T) {
+	if got := osc52Sequence("hi"); got != "\x1b]52;c;aGk=\a" {
+		t.Errorf("Expected base64 encoded content in the escape sequence, got %q", got)
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	tempDir := t.TempDir()
+	filePath := filepath.Join(tempDir, "data.json")
+	if err := os.WriteFile(filePath, []byte(`{"old": true}`), 0600); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	content := `{"new": true}`
+	if err := WriteFileAtomic(filePath, content); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(data) != content {
+		t.Errorf("Expected content %q, got %q", content, string(data))
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		t.Fatalf("Failed to stat file: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected the permissions to be kept, got %v", info.Mode().Perm())
+	}
+
+	// No temporary file is left behind
+	entries, _ := os.ReadDir(tempDir)
+	if len(entries) != 1 {
+		t.Errorf("Expected only the written file in the directory, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,115 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+)
+
+// MaxDecompressedSize is the most data compressed input may decompress to,
+// guarding against decompression bombs
+var MaxDecompressedSize int64 = 1 << 30
+
+// Compression describes how input was compressed
+type Compression struct {
+	Format         string // gzip, zstd, bzip2 or xz
+	CompressedSize int64  // Bytes read
+	Size           int64  // Bytes after decompressing
+}
+
+// String shows the format and both sizes, e.g. "gzip 1.2 MiB → 8.4 MiB"
+func (c Compression) String() string {
+	return fmt.Sprintf("%s %s → %s", c.Format, FormatSize(c.CompressedSize), FormatSize(c.Size))
+}
+
+// compressionFormats are the formats detected, by the magic bytes their data starts with
+var compressionFormats = []struct {
+	name  string
+	magic []byte
+	open  func(r io.Reader) (io.ReadCloser, error)
+}{
+	{"gzip", []byte{0x1f, 0x8b}, func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r)
+	}},
+	{"zstd", []byte{0x28, 0xb5, 0x2f, 0xfd}, func(r io.Reader) (io.ReadCloser, error) {
+		decoder, err := zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	}},
+	{"bzip2", []byte("BZh"), func(r io.Reader) (io.ReadCloser, error) {
+		return io.NopCloser(bzip2.NewReader(r)), nil
+	}},
+	{"xz", []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}, func(r io.Reader) (io.ReadCloser, error) {
+		reader, err := xz.NewReader(r)
+		return io.NopCloser(reader), err
+	}},
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// readAll reads r, decompressing it while reading if it starts with the
+// magic bytes of a compression format. The Compression is nil for data that
+// isn't compressed.
+func readAll(r io.Reader) ([]byte, *Compression, error) {
+	counter := &countingReader{r: r}
+	buffered := bufio.NewReader(counter)
+	// Peek fails on input shorter than the longest magic, which is then checked as is
+	head, _ := buffered.Peek(6)
+
+	for _, format := range compressionFormats {
+		if !bytes.HasPrefix(head, format.magic) {
+			continue
+		}
+		decompressed, err := format.open(buffered)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s data: %w", format.name, err)
+		}
+		defer decompressed.Close()
+
+		data, err := io.ReadAll(io.LimitReader(decompressed, MaxDecompressedSize+1))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error decompressing %s data: %w", format.name, err)
+		}
+		if int64(len(data)) > MaxDecompressedSize {
+			return nil, nil, fmt.Errorf("%s data decompresses to more than %s", format.name, FormatSize(MaxDecompressedSize))
+		}
+		// Count data after the compressed stream too
+		io.Copy(io.Discard, buffered)
+		return data, &Compression{Format: format.name, CompressedSize: counter.n, Size: int64(len(data))}, nil
+	}
+
+	data, err := io.ReadAll(buffered)
+	return data, nil, err
+}
+
+// FormatSize shows a number of bytes in the largest binary unit it fills, e.g. "1.5 KiB"
+func FormatSize(bytes int64) string {
+	if bytes < 1024 {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	units := []string{"KiB", "MiB", "GiB"}
+	size, unit := float64(bytes)/1024, 0
+	for size >= 1024 && unit < len(units)-1 {
+		size /= 1024
+		unit++
+	}
+	return fmt.Sprintf("%.1f %s", size, units[unit])
+}
@@ -0,0 +1,207 @@
+package input
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+)
+
+// Lenient makes every input be read as JSONC/JSON5, as files ending in
+// .jsonc or .json5 always are
+var Lenient bool
+
+// Relaxations that lenient input may use, as reported by Relax
+const (
+	RelaxedComments       = "comments"
+	RelaxedTrailingCommas = "trailing commas"
+	RelaxedSingleQuotes   = "single quotes"
+	RelaxedUnquotedKeys   = "unquoted keys"
+)
+
+// IsLenientFile reports whether a file is read as JSONC/JSON5 by its extension
+func IsLenientFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonc", ".json5":
+		return true
+	}
+	return false
+}
+
+// Relax rewrites JSONC/JSON5 as strict JSON by removing comments and trailing
+// commas, and double-quoting single-quoted strings and unquoted keys. Line
+// breaks are kept so lines stay where they were. It returns which of these
+// relaxations the data used. The result is not validated.
+func Relax(data []byte) ([]byte, []string) {
+	r := relaxer{data: data}
+	r.run()
+	return r.out, r.relaxed
+}
+
+// relaxer scans data once, writing strict JSON to out
+type relaxer struct {
+	data    []byte
+	pos     int
+	out     []byte
+	relaxed []string
+}
+
+// note records that a relaxation was used
+func (r *relaxer) note(relaxation string) {
+	for _, seen := range r.relaxed {
+		if seen == relaxation {
+			return
+		}
+	}
+	r.relaxed = append(r.relaxed, relaxation)
+}
+
+func (r *relaxer) run() {
+	for r.pos < len(r.data) {
+		c := r.data[r.pos]
+		switch {
+		case c == '"':
+			r.copyString()
+		case c == '\'':
+			r.note(RelaxedSingleQuotes)
+			r.quoteString()
+		case r.comment():
+			r.note(RelaxedComments)
+		case c == ',' && r.closesNext():
+			r.note(RelaxedTrailingCommas)
+			r.pos++
+		case isIdentifierStart(c):
+			r.identifier()
+		default:
+			r.out = append(r.out, c)
+			r.pos++
+		}
+	}
+}
+
+// copyString copies a double-quoted string as it is
+func (r *relaxer) copyString() {
+	start := r.pos
+	r.pos++
+	for r.pos < len(r.data) && r.data[r.pos] != '"' {
+		if r.data[r.pos] == '\\' {
+			r.pos++
+		}
+		r.pos++
+	}
+	r.pos = min(r.pos+1, len(r.data))
+	r.out = append(r.out, r.data[start:r.pos]...)
+}
+
+// quoteString writes a single-quoted string with double quotes, escaping
+// double quotes inside it and unescaping single quotes
+func (r *relaxer) quoteString() {
+	r.out = append(r.out, '"')
+	r.pos++
+	for r.pos < len(r.data) && r.data[r.pos] != '\'' {
+		c := r.data[r.pos]
+		switch {
+		case c == '\\' && r.pos+1 < len(r.data) && r.data[r.pos+1] == '\'':
+			r.out = append(r.out, '\'')
+			r.pos++
+		case c == '\\' && r.pos+1 < len(r.data):
+			r.out = append(r.out, c, r.data[r.pos+1])
+			r.pos++
+		case c == '"':
+			r.out = append(r.out, '\\', '"')
+		default:
+			r.out = append(r.out, c)
+		}
+		r.pos++
+	}
+	r.out = append(r.out, '"')
+	r.pos = min(r.pos+1, len(r.data))
+}
+
+// comment skips a // or /* */ comment at the position, keeping its line
+// breaks, and reports whether there was one
+func (r *relaxer) comment() bool {
+	end := commentEnd(r.data, r.pos)
+	if end < 0 {
+		return false
+	}
+	for _, c := range r.data[r.pos:end] {
+		if c == '\n' {
+			r.out = append(r.out, '\n')
+		}
+	}
+	r.pos = end
+	return true
+}
+
+// commentEnd returns where the comment starting at pos ends, or -1 if there
+// is no comment there. Unterminated block comments run to the end.
+func commentEnd(data []byte, pos int) int {
+	if pos+1 >= len(data) || data[pos] != '/' {
+		return -1
+	}
+	switch data[pos+1] {
+	case '/':
+		if end := bytes.IndexByte(data[pos:], '\n'); end >= 0 {
+			return pos + end
+		}
+		return len(data)
+	case '*':
+		if end := bytes.Index(data[pos+2:], []byte("*/")); end >= 0 {
+			return pos + 2 + end + 2
+		}
+		return len(data)
+	}
+	return -1
+}
+
+// nextToken returns the position of the next character after pos that isn't
+// whitespace or in a comment
+func nextToken(data []byte, pos int) int {
+	for pos < len(data) {
+		switch data[pos] {
+		case ' ', '\t', '\n', '\r':
+			pos++
+			continue
+		}
+		end := commentEnd(data, pos)
+		if end < 0 {
+			return pos
+		}
+		pos = end
+	}
+	return pos
+}
+
+// closesNext reports whether the comma at the position is followed by the
+// end of an object or array
+func (r *relaxer) closesNext() bool {
+	next := nextToken(r.data, r.pos+1)
+	return next < len(r.data) && (r.data[next] == '}' || r.data[next] == ']')
+}
+
+// identifier copies a bare word, quoting it if it is an object key
+func (r *relaxer) identifier() {
+	start := r.pos
+	for r.pos < len(r.data) && isIdentifierPart(r.data[r.pos]) {
+		r.pos++
+	}
+	word := r.data[start:r.pos]
+
+	next := nextToken(r.data, r.pos)
+	if next < len(r.data) && r.data[next] == ':' {
+		r.note(RelaxedUnquotedKeys)
+		r.out = append(r.out, '"')
+		r.out = append(r.out, word...)
+		r.out = append(r.out, '"')
+		return
+	}
+	r.out = append(r.out, word...)
+}
+
+func isIdentifierStart(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '$'
+}
+
+func isIdentifierPart(c byte) bool {
+	return isIdentifierStart(c) || c >= '0' && c <= '9'
+}
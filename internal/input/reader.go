@@ -2,58 +2,140 @@ package input
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
+// ErrNoStdin is returned when nothing was piped to stdin
+var ErrNoStdin = errors.New("no data received from stdin")
+
+// Content is JSON read from a file or stdin
+type Content struct {
+	JSON        string
+	Compression *Compression // How the input was compressed, nil if it wasn't
+	Relaxed     []string     // Syntax beyond strict JSON the input used, see Relax
+}
+
 // ReadFromFile reads JSON data from a file at the given path.
 // It returns the raw JSON as a string and any error encountered.
 // The JSON is validated before being returned.
 func ReadFromFile(path string) (string, error) {
+	content, err := ReadFileContent(path)
+	return content.JSON, err
+}
+
+// ReadFileContent reads JSON data from a file like ReadFromFile, decompressing
+// it if it is compressed. Files are read as JSONC/JSON5 if Lenient is set or
+// their extension is .jsonc or .json5.
+func ReadFileContent(path string) (Content, error) {
 	// Check if file exists
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return "", fmt.Errorf("file does not exist: %s", path)
+		return Content{}, fmt.Errorf("file does not exist: %s", path)
 	}
 
 	// Read the file
-	data, err := os.ReadFile(path)
+	file, err := os.Open(path)
 	if err != nil {
-		return "", fmt.Errorf("error reading file: %w", err)
+		return Content{}, fmt.Errorf("error reading file: %w", err)
+	}
+	defer file.Close()
+	data, compression, err := readAll(file)
+	if err != nil {
+		return Content{}, fmt.Errorf("error reading file %s: %w", path, err)
 	}
 
 	// Check for empty file
 	if len(data) == 0 {
-		return "", fmt.Errorf("file is empty: %s", path)
+		return Content{}, fmt.Errorf("file is empty: %s", path)
 	}
 
 	// Validate JSON
-	if !json.Valid(data) {
-		return "", fmt.Errorf("invalid JSON in file: %s", path)
+	data, relaxed, ok := strictJSON(data, Lenient || IsLenientFile(path))
+	if !ok {
+		return Content{}, fmt.Errorf("invalid JSON in file: %s", path)
 	}
 
-	return string(data), nil
+	return Content{JSON: string(data), Compression: compression, Relaxed: relaxed}, nil
 }
 
 // ReadFromStdin reads JSON data from standard input.
 // It returns the raw JSON as a string and any error encountered.
 // The JSON is validated before being returned.
 func ReadFromStdin() (string, error) {
+	content, err := ReadStdinContent()
+	return content.JSON, err
+}
+
+// ReadStdinContent reads JSON data from standard input like ReadFromStdin,
+// decompressing it if it is compressed. It is read as JSONC/JSON5 if Lenient
+// is set.
+func ReadStdinContent() (Content, error) {
 	// Read all data from stdin
-	data, err := io.ReadAll(os.Stdin)
+	data, compression, err := readAll(os.Stdin)
 	if err != nil {
-		return "", fmt.Errorf("error reading from stdin: %w", err)
+		return Content{}, fmt.Errorf("error reading from stdin: %w", err)
 	}
 
 	// Check for empty input
 	if len(data) == 0 {
-		return "", fmt.Errorf("no data received from stdin")
+		return Content{}, ErrNoStdin
 	}
 
 	// Validate JSON
-	if !json.Valid(data) {
-		return "", fmt.Errorf("invalid JSON from stdin")
+	data, relaxed, ok := strictJSON(data, Lenient)
+	if !ok {
+		return Content{}, fmt.Errorf("invalid JSON from stdin")
+	}
+
+	return Content{JSON: string(data), Compression: compression, Relaxed: relaxed}, nil
+}
+
+// strictJSON returns data if it is valid JSON. If lenient is set, JSONC/JSON5
+// is rewritten as strict JSON, returning the relaxations it used.
+func strictJSON(data []byte, lenient bool) ([]byte, []string, bool) {
+	if json.Valid(data) {
+		return data, nil, true
+	}
+	if !lenient {
+		return nil, nil, false
+	}
+	strict, relaxed := Relax(data)
+	return strict, relaxed, json.Valid(strict)
+}
+
+// ExpandPaths expands glob patterns in the given arguments into file paths.
+// Arguments without glob metacharacters are returned unchanged so that
+// ReadFromFile can report missing files. Duplicate paths are removed while
+// preserving order.
+func ExpandPaths(args []string) ([]string, error) {
+	paths := []string{}
+	seen := make(map[string]bool)
+
+	for _, arg := range args {
+		matches := []string{arg}
+
+		if strings.ContainsAny(arg, "*?[") {
+			var err error
+			matches, err = filepath.Glob(arg)
+			if err != nil {
+				return nil, fmt.Errorf("invalid glob pattern %q: %w", arg, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no files match pattern: %s", arg)
+			}
+		}
+
+		for _, match := range matches {
+			if seen[match] {
+				continue
+			}
+			seen[match] = true
+			paths = append(paths, match)
+		}
 	}
 
-	return string(data), nil
+	return paths, nil
 }
@@ -1,10 +1,17 @@
 package input
 
 import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
 )
 
 func TestReadFromFile(t *testing.T) {
@@ -202,8 +209,205 @@ func TestReadFromStdin(t *testing.T) {
 		if err == nil {
 			t.Error("Expected error for empty stdin, got nil")
 		}
-		if !strings.Contains(err.Error(), "no data") {
-			t.Errorf("Expected error message to contain 'no data', got: %v", err)
+		if !errors.Is(err, ErrNoStdin) {
+			t.Errorf("Expected ErrNoStdin, got: %v", err)
+		}
+	})
+}
+
+func TestExpandPaths(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, name := range []string{"a.json", "b.json", "c.txt"} {
+		if err := os.WriteFile(filepath.Join(tempDir, name), []byte(`{}`), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+	}
+
+	t.Run("plain paths are kept", func(t *testing.T) {
+		missing := filepath.Join(tempDir, "missing.json")
+		paths, err := ExpandPaths([]string{missing})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if len(paths) != 1 || paths[0] != missing {
+			t.Errorf("Expected [%s], got %v", missing, paths)
+		}
+	})
+
+	t.Run("glob pattern", func(t *testing.T) {
+		paths, err := ExpandPaths([]string{filepath.Join(tempDir, "*.json")})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		expected := []string{filepath.Join(tempDir, "a.json"), filepath.Join(tempDir, "b.json")}
+		if strings.Join(paths, ",") != strings.Join(expected, ",") {
+			t.Errorf("Expected %v, got %v", expected, paths)
+		}
+	})
+
+	t.Run("duplicates removed", func(t *testing.T) {
+		a := filepath.Join(tempDir, "a.json")
+		paths, err := ExpandPaths([]string{a, filepath.Join(tempDir, "*.json")})
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+		if len(paths) != 2 || paths[0] != a {
+			t.Errorf("Expected a.json first and no duplicates, got %v", paths)
+		}
+	})
+
+	t.Run("glob without matches", func(t *testing.T) {
+		_, err := ExpandPaths([]string{filepath.Join(tempDir, "*.yaml")})
+		if err == nil {
+			t.Error("Expected error for pattern without matches, got nil")
+		}
+	})
+}
+
+// bzip2JSON is {"a":1} compressed with bzip2, which the standard library can't write
+var bzip2JSON = []byte{0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x3a, 0xdf, 0x03, 0x60, 0x00, 0x00,
+	0x02, 0x99, 0x80, 0x10, 0x00, 0x20, 0x10, 0x20, 0x00, 0x00, 0x0a, 0x20, 0x00, 0x21, 0x80, 0x0c, 0x02, 0x5b, 0x06,
+	0xdc, 0x5d, 0xc9, 0x14, 0xe1, 0x42, 0x40, 0xeb, 0x7c, 0x0d, 0x80}
+
+func TestReadCompressed(t *testing.T) {
+	tempDir := t.TempDir()
+	jsonData := `{"a":1}`
+
+	var gz, zst, xzData bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	gw.Write([]byte(jsonData))
+	gw.Close()
+	zw, _ := zstd.NewWriter(&zst)
+	zw.Write([]byte(jsonData))
+	zw.Close()
+	xw, _ := xz.NewWriter(&xzData)
+	xw.Write([]byte(jsonData))
+	xw.Close()
+
+	tests := []struct {
+		format string
+		data   []byte
+	}{
+		{"gzip", gz.Bytes()},
+		{"zstd", zst.Bytes()},
+		{"bzip2", bzip2JSON},
+		{"xz", xzData.Bytes()},
+	}
+	for _, tt := range tests {
+		filePath := filepath.Join(tempDir, "data."+tt.format)
+		if err := os.WriteFile(filePath, tt.data, 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+
+		content, err := ReadFileContent(filePath)
+		if err != nil {
+			t.Errorf("%s: expected no error, got: %v", tt.format, err)
+			continue
+		}
+		if content.JSON != jsonData {
+			t.Errorf("%s: expected %s, got %s", tt.format, jsonData, content.JSON)
+		}
+		expected := Compression{Format: tt.format, CompressedSize: int64(len(tt.data)), Size: int64(len(jsonData))}
+		if content.Compression == nil || *content.Compression != expected {
+			t.Errorf("%s: expected %+v, got %+v", tt.format, expected, content.Compression)
+		}
+	}
+
+	t.Run("plain JSON", func(t *testing.T) {
+		filePath := filepath.Join(tempDir, "plain.json")
+		os.WriteFile(filePath, []byte(jsonData), 0644)
+		content, err := ReadFileContent(filePath)
+		if err != nil || content.Compression != nil {
+			t.Errorf("Expected uncompressed content, got %+v, %v", content, err)
+		}
+	})
+
+	t.Run("decompression bomb", func(t *testing.T) {
+		limit := MaxDecompressedSize
+		defer func() { MaxDecompressedSize = limit }()
+		MaxDecompressedSize = 4
+
+		filePath := filepath.Join(tempDir, "data.gzip")
+		_, err := ReadFromFile(filePath)
+		if err == nil || !strings.Contains(err.Error(), "decompresses to more than 4 B") {
+			t.Errorf("Expected the size guard to stop decompressing, got: %v", err)
 		}
 	})
 }
+
+func TestFormatSize(t *testing.T) {
+	tests := map[int64]string{
+		512:     "512 B",
+		1536:    "1.5 KiB",
+		3 << 20: "3.0 MiB",
+		5 << 40: "5120.0 GiB",
+	}
+	for size, expected := range tests {
+		if got := FormatSize(size); got != expected {
+			t.Errorf("Expected %s for %d bytes, got %s", expected, size, got)
+		}
+	}
+}
+
+func TestRelax(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		relaxed  []string
+	}{
+		{"strict", `{"a": [1, 2]}`, `{"a": [1, 2]}`, nil},
+		{"line comment", "{\"a\": 1 // one\n}", "{\"a\": 1 \n}", []string{RelaxedComments}},
+		{"block comment", "/* a\nb */[1]", "\n[1]", []string{RelaxedComments}},
+		{"trailing commas", `{"a": [1, 2,], "b": 3, /* last */ }`, `{"a": [1, 2], "b": 3  }`, []string{RelaxedTrailingCommas, RelaxedComments}},
+		{"single quotes", `['it\'s', 'say "hi"']`, `["it's", "say \"hi\""]`, []string{RelaxedSingleQuotes}},
+		{"unquoted keys", `{a_1: true, $b : null}`, `{"a_1": true, "$b" : null}`, []string{RelaxedUnquotedKeys}},
+		{"strings are kept", `{"// not a comment": "a, }"}`, `{"// not a comment": "a, }"}`, nil},
+	}
+	for _, tt := range tests {
+		output, relaxed := Relax([]byte(tt.input))
+		if string(output) != tt.expected {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.expected, output)
+		}
+		if strings.Join(relaxed, ",") != strings.Join(tt.relaxed, ",") {
+			t.Errorf("%s: expected relaxations %v, got %v", tt.name, tt.relaxed, relaxed)
+		}
+	}
+}
+
+func TestReadLenient(t *testing.T) {
+	tempDir := t.TempDir()
+	settings := "{\n  // Editor settings\n  'editor.fontSize': 14,\n  files: {exclude: ['node_modules',],},\n}\n"
+
+	for _, name := range []string{"settings.jsonc", "settings.JSON5"} {
+		filePath := filepath.Join(tempDir, name)
+		if err := os.WriteFile(filePath, []byte(settings), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
+		}
+		content, err := ReadFileContent(filePath)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got: %v", name, err)
+		}
+		if !json.Valid([]byte(content.JSON)) || len(content.Relaxed) != 4 {
+			t.Errorf("%s: expected strict JSON using every relaxation, got %s %v", name, content.JSON, content.Relaxed)
+		}
+	}
+
+	// Other files are only read leniently when asked to
+	filePath := filepath.Join(tempDir, "settings.json")
+	os.WriteFile(filePath, []byte(settings), 0644)
+	if _, err := ReadFromFile(filePath); err == nil {
+		t.Error("Expected an error for comments in a .json file")
+	}
+	Lenient = true
+	defer func() { Lenient = false }()
+	if _, err := ReadFromFile(filePath); err != nil {
+		t.Errorf("Expected lenient mode to read the file, got: %v", err)
+	}
+
+	// Invalid JSON5 is still an error
+	os.WriteFile(filePath, []byte(`{a: 0x10}`), 0644)
+	if _, err := ReadFromFile(filePath); err == nil {
+		t.Error("Expected an error for syntax that isn't relaxed")
+	}
+}
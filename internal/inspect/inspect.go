@@ -0,0 +1,287 @@
+// Package inspect decodes scalar JSON values that hold encoded data, such as
+// timestamps, dates, base64, JWTs, URLs and JSON inside strings
+package inspect
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tidwall/gjson"
+)
+
+// maxDumpBytes is how many decoded bytes the hex dump of base64 shows
+const maxDumpBytes = 256
+
+// dateLayout formats dates in the interpretations
+const dateLayout = "2006-01-02 15:04:05.999999999 MST"
+
+// KindJSONString is the kind of strings holding JSON, which gjson can read
+// with the @fromstr modifier
+const KindJSONString = "JSON string"
+
+// Interpretation is one way of decoding a value
+type Interpretation struct {
+	Kind  string   // What the value was decoded as, e.g. "Unix time (seconds)"
+	Lines []string // The decoded value, e.g. the date in UTC and local time
+	JSON  string   // JSON found in the value that can be explored, if any
+}
+
+// Inspect returns the interpretations of a string or number, showing dates
+// in UTC and in loc. Objects, arrays and values that don't decode have none.
+func Inspect(value gjson.Result, loc *time.Location) []Interpretation {
+	var found []Interpretation
+	add := func(i *Interpretation) {
+		if i != nil {
+			found = append(found, *i)
+		}
+	}
+
+	switch value.Type {
+	case gjson.Number:
+		add(unixTime(value.Raw, loc))
+	case gjson.String:
+		s := value.String()
+		add(unixTime(s, loc))
+		add(date(s, loc))
+		add(jsonString(s))
+		add(jwt(s, loc))
+		add(link(s))
+		add(urlEncoded(s))
+		add(base64Blob(s))
+	}
+	return found
+}
+
+// unixTimeUnits are the units of Unix timestamps, by the range of plausible
+// values from 1973 to 2286 in that unit
+var unixTimeUnits = []struct {
+	name    string
+	seconds float64
+}{
+	{"seconds", 1},
+	{"milliseconds", 1e-3},
+	{"microseconds", 1e-6},
+	{"nanoseconds", 1e-9},
+}
+
+// unixTime interprets a number as a Unix timestamp in the unit its size suggests
+func unixTime(number string, loc *time.Location) *Interpretation {
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || math.IsInf(n, 0) || strings.ContainsAny(number, "eE") {
+		return nil
+	}
+	for _, unit := range unixTimeUnits {
+		seconds := n * unit.seconds
+		if seconds < 1e8 || seconds >= 1e10 {
+			continue
+		}
+		whole, frac := math.Modf(seconds)
+		t := time.Unix(int64(whole), int64(frac*1e9))
+		return &Interpretation{Kind: "Unix time (" + unit.name + ")", Lines: dateLines(t, loc)}
+	}
+	return nil
+}
+
+// dateLayouts are the formats dates in strings are recognized in
+var dateLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", time.RFC1123Z, time.RFC1123, "2006-01-02"}
+
+// date interprets a string as a date
+func date(s string, loc *time.Location) *Interpretation {
+	for _, layout := range dateLayouts {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			continue
+		}
+		lines := append(dateLines(t, loc), fmt.Sprintf("Unix:  %d", t.Unix()))
+		return &Interpretation{Kind: "Date", Lines: lines}
+	}
+	return nil
+}
+
+// dateLines shows a time in UTC and in loc
+func dateLines(t time.Time, loc *time.Location) []string {
+	return []string{
+		"UTC:   " + t.UTC().Format(dateLayout),
+		"Local: " + t.In(loc).Format(dateLayout),
+	}
+}
+
+// jsonString interprets a string holding a JSON object or array
+func jsonString(s string) *Interpretation {
+	trimmed := strings.TrimSpace(s)
+	if !strings.HasPrefix(trimmed, "{") && !strings.HasPrefix(trimmed, "[") || !gjson.Valid(trimmed) {
+		return nil
+	}
+	return &Interpretation{Kind: KindJSONString, Lines: prettyLines(trimmed), JSON: trimmed}
+}
+
+// jwtClaimDates are the registered JWT claims that hold Unix times
+var jwtClaimDates = []string{"exp", "iat", "nbf"}
+
+// jwt interprets a string as a JSON Web Token, showing its header and claims
+func jwt(s string, loc *time.Location) *Interpretation {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+	header, err1 := base64.RawURLEncoding.DecodeString(parts[0])
+	claims, err2 := base64.RawURLEncoding.DecodeString(parts[1])
+	if err1 != nil || err2 != nil || !gjson.ValidBytes(header) || !gjson.ValidBytes(claims) ||
+		!gjson.GetBytes(header, "alg").Exists() || !gjson.ParseBytes(claims).IsObject() {
+		return nil
+	}
+
+	lines := append([]string{"Header:"}, prettyLines(string(header))...)
+	lines = append(lines, "Claims:")
+	lines = append(lines, prettyLines(string(claims))...)
+	for _, claim := range jwtClaimDates {
+		if value := gjson.GetBytes(claims, claim); value.Type == gjson.Number {
+			t := time.Unix(value.Int(), 0)
+			lines = append(lines, fmt.Sprintf("%s: %s (%s)", claim, t.UTC().Format(dateLayout), t.In(loc).Format(dateLayout)))
+		}
+	}
+	return &Interpretation{Kind: "JWT", Lines: lines, JSON: string(claims)}
+}
+
+// link interprets a string as an absolute URL, listing its parts
+func link(s string) *Interpretation {
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" || strings.ContainsAny(s, " \t\n") {
+		return nil
+	}
+
+	lines := []string{"Scheme:   " + u.Scheme, "Host:     " + u.Host}
+	if u.User != nil {
+		lines = append(lines, "User:     "+u.User.Username())
+	}
+	if u.Path != "" {
+		lines = append(lines, "Path:     "+u.Path)
+	}
+	lines = append(lines, queryLines(u.Query())...)
+	if u.Fragment != "" {
+		lines = append(lines, "Fragment: "+u.Fragment)
+	}
+	return &Interpretation{Kind: "URL", Lines: lines}
+}
+
+// percentEscape matches a percent-encoded byte
+var percentEscape = regexp.MustCompile(`%[0-9A-Fa-f]{2}`)
+
+// urlEncoded interprets a string that isn't a URL as URL-encoded text, or as
+// a query string if it has parameters
+func urlEncoded(s string) *Interpretation {
+	if !percentEscape.MatchString(s) || link(s) != nil {
+		return nil
+	}
+	decoded, err := url.QueryUnescape(s)
+	if err != nil {
+		return nil
+	}
+
+	lines := []string{"Decoded: " + decoded}
+	if strings.Contains(s, "=") {
+		if query, err := url.ParseQuery(s); err == nil {
+			lines = append(lines, queryLines(query)...)
+		}
+	}
+	return &Interpretation{Kind: "URL-encoded", Lines: lines}
+}
+
+// queryLines lists the parameters of a query string sorted by name
+func queryLines(query url.Values) []string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range names {
+		for _, value := range query[name] {
+			lines = append(lines, fmt.Sprintf("Query:    %s = %s", name, value))
+		}
+	}
+	return lines
+}
+
+// base64Encodings are tried in order; the padded ones reject unpadded input
+var base64Encodings = []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding}
+
+// hexDigits matches strings such as numbers and hashes, which are more
+// likely hexadecimal than base64
+var hexDigits = regexp.MustCompile(`^[0-9A-Fa-f]+$`)
+
+// base64Blob interprets a string as base64. Short strings could be words, so
+// they must decode to text.
+func base64Blob(s string) *Interpretation {
+	if len(s) < 8 || hexDigits.MatchString(s) {
+		return nil
+	}
+	var decoded []byte
+	for _, encoding := range base64Encodings {
+		if data, err := encoding.DecodeString(s); err == nil {
+			decoded = data
+			break
+		}
+	}
+	text := printable(decoded)
+	if decoded == nil || (!text && len(s) < 16) {
+		return nil
+	}
+
+	var lines []string
+	interpretation := &Interpretation{Kind: fmt.Sprintf("Base64 (%d bytes)", len(decoded))}
+	if text {
+		lines = append(lines, "Text:")
+		lines = append(lines, strings.Split(strings.TrimRight(string(decoded), "\n"), "\n")...)
+		if trimmed := strings.TrimSpace(string(decoded)); gjson.Valid(trimmed) && (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) {
+			interpretation.JSON = trimmed
+		}
+	}
+	lines = append(lines, "Hex:")
+	dump := decoded
+	if len(dump) > maxDumpBytes {
+		dump = dump[:maxDumpBytes]
+	}
+	lines = append(lines, strings.Split(strings.TrimRight(hex.Dump(dump), "\n"), "\n")...)
+	if len(decoded) > maxDumpBytes {
+		lines = append(lines, fmt.Sprintf("… %d more bytes", len(decoded)-maxDumpBytes))
+	}
+	interpretation.Lines = lines
+	return interpretation
+}
+
+// printable reports whether data is UTF-8 text without control characters
+// other than whitespace
+func printable(data []byte) bool {
+	if len(data) == 0 || !utf8.Valid(data) {
+		return false
+	}
+	for _, r := range string(data) {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// prettyLines returns the lines of JSON indented two spaces per level
+func prettyLines(data string) []string {
+	var b bytes.Buffer
+	if err := json.Indent(&b, []byte(data), "", "  "); err != nil {
+		return []string{data}
+	}
+	return strings.Split(b.String(), "\n")
+}
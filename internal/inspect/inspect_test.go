@@ -0,0 +1,75 @@
+package inspect
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// kinds returns the kinds of the interpretations of a JSON value
+func kinds(raw string) []string {
+	var kinds []string
+	for _, i := range Inspect(gjson.Parse(raw), time.UTC) {
+		kinds = append(kinds, i.Kind)
+	}
+	return kinds
+}
+
+func TestInspectKinds(t *testing.T) {
+	jwt := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"42","exp":1700000000}`)) + ".c2lnbmF0dXJl"
+
+	tests := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{"seconds", `1700000000`, "Unix time (seconds)"},
+		{"milliseconds", `1700000000123`, "Unix time (milliseconds)"},
+		{"timestamp string", `"1700000000"`, "Unix time (seconds)"},
+		{"small number", `42`, ""},
+		{"rfc3339", `"2023-11-14T22:13:20Z"`, "Date"},
+		{"plain date", `"2023-11-14"`, "Date"},
+		{"json string", `"{\"a\": [1, 2]}"`, "JSON string"},
+		{"jwt", `"` + jwt + `"`, "JWT"},
+		{"url", `"https://example.com/search?q=dive&page=2#top"`, "URL"},
+		{"url-encoded", `"name%3DJohn%20Doe"`, "URL-encoded"},
+		{"base64 text", `"aGVsbG8gd29ybGQ="`, "Base64 (11 bytes)"},
+		{"word", `"Password"`, ""},
+		{"hash", `"d41d8cd98f00b204e9800998ecf8427e"`, ""},
+		{"object", `{"a": 1}`, ""},
+		{"boolean", `true`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(kinds(tt.raw), ", ")
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestInspectLines(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+	found := Inspect(gjson.Parse(`1700000000`), loc)
+	expected := []string{"UTC:   2023-11-14 22:13:20 UTC", "Local: 2023-11-14 23:13:20 CET"}
+	if len(found) != 1 || strings.Join(found[0].Lines, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("Expected %q, got %+v", expected, found)
+	}
+
+	found = Inspect(gjson.Parse(`"https://user@example.com/a?b=1&b=2"`), time.UTC)
+	lines := strings.Join(found[0].Lines, "\n")
+	if !strings.Contains(lines, "User:     user") || !strings.Contains(lines, "Query:    b = 1\nQuery:    b = 2") {
+		t.Errorf("Expected the parts of the URL, got:\n%s", lines)
+	}
+
+	// JSON in base64 can be explored
+	found = Inspect(gjson.Parse(`"`+base64.StdEncoding.EncodeToString([]byte(`{"id": 7}`))+`"`), time.UTC)
+	if len(found) != 1 || found[0].JSON != `{"id": 7}` || !strings.Contains(strings.Join(found[0].Lines, "\n"), "00000000  7b 22 69 64") {
+		t.Errorf("Expected decoded JSON and a hex dump, got %+v", found)
+	}
+}
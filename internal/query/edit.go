@@ -0,0 +1,220 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// member is a value stored in an object or array of a document, with the
+// byte offsets needed to change it in place
+type member struct {
+	parent    gjson.Result
+	key       gjson.Result // Key of object members
+	value     gjson.Result
+	start     int // Offset of the key, or of the value in arrays
+	end       int // Offset after the value
+	index     int // Position in the parent
+	prevEnd   int // Offset after the previous member, -1 for the first
+	nextStart int // Offset of the next member, -1 for the last
+}
+
+// locate returns the value at path, which must be stored in the document
+// rather than computed by a query or modifier
+func locate(doc, path string) (gjson.Result, error) {
+	if path == "" {
+		root := gjson.Parse(doc)
+		root.Raw = strings.TrimSpace(root.Raw)
+		root.Index = strings.Index(doc, root.Raw)
+		return root, nil
+	}
+
+	r := gjson.Get(doc, path)
+	if !r.Exists() {
+		return r, fmt.Errorf("%s does not exist", path)
+	}
+	if r.Index <= 0 || r.Index+len(r.Raw) > len(doc) || doc[r.Index:r.Index+len(r.Raw)] != r.Raw {
+		return r, fmt.Errorf("%s is computed by a query or modifier and can't be edited", path)
+	}
+	return r, nil
+}
+
+// locateMember returns the member of an object or array at path
+func locateMember(doc, path string) (member, error) {
+	if path == "" {
+		return member{}, fmt.Errorf("the whole document is not in an object or array")
+	}
+	value, err := locate(doc, path)
+	if err != nil {
+		return member{}, err
+	}
+	parentPath, _ := ParentPath(path)
+	parent, err := locate(doc, parentPath)
+	if err != nil {
+		return member{}, err
+	}
+
+	m := member{parent: parent, index: -1, prevEnd: -1, nextStart: -1}
+	prevEnd, index := -1, 0
+	parent.ForEach(func(k, v gjson.Result) bool {
+		start := v.Index
+		if parent.IsObject() {
+			start = k.Index
+		}
+		switch {
+		case m.index >= 0:
+			m.nextStart = start
+			return false
+		case v.Index == value.Index:
+			m.key, m.value, m.start, m.end = k, v, start, v.Index+len(v.Raw)
+			m.index, m.prevEnd = index, prevEnd
+		}
+		prevEnd = v.Index + len(v.Raw)
+		index++
+		return true
+	})
+	if m.index < 0 {
+		return m, fmt.Errorf("%s is computed by a query or modifier and can't be edited", path)
+	}
+	return m, nil
+}
+
+// Set replaces the value at path in a document with raw JSON, keeping the
+// rest of the document as it is
+func Set(doc, path, raw string) (string, error) {
+	if !gjson.Valid(raw) {
+		return "", fmt.Errorf("invalid JSON: %s", raw)
+	}
+	value, err := locate(doc, path)
+	if err != nil {
+		return "", err
+	}
+	return doc[:value.Index] + raw + doc[value.Index+len(value.Raw):], nil
+}
+
+// Delete removes the value at path, with its key, from its object or array
+func Delete(doc, path string) (string, error) {
+	m, err := locateMember(doc, path)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case m.prevEnd >= 0:
+		return doc[:m.prevEnd] + doc[m.end:], nil
+	case m.nextStart >= 0:
+		return doc[:m.start] + doc[m.nextStart:], nil
+	}
+	// The only member leaves an empty object or array
+	open, close := m.parent.Index, m.parent.Index+len(m.parent.Raw)-1
+	return doc[:open+1] + doc[close:], nil
+}
+
+// Rename changes the key of the object member at path. It returns the new
+// document and the path of the member.
+func Rename(doc, path, key string) (string, string, error) {
+	m, err := locateMember(doc, path)
+	if err != nil {
+		return "", "", err
+	}
+	if !m.parent.IsObject() {
+		return "", "", fmt.Errorf("%s is an array element, which has no key", path)
+	}
+	if key == m.key.String() {
+		return doc, path, nil
+	}
+	if m.parent.Get(gjson.Escape(key)).Exists() {
+		return "", "", fmt.Errorf("key %q already exists", key)
+	}
+
+	parentPath, _ := ParentPath(path)
+	return doc[:m.key.Index] + quote(key) + doc[m.key.Index+len(m.key.Raw):], JoinPath(parentPath, gjson.Escape(key)), nil
+}
+
+// Append adds raw JSON at the end of the object or array at path, written
+// as "key": value for objects. It returns the new document and the path of
+// the added value.
+func Append(doc, path, raw string) (string, string, error) {
+	target, err := locate(doc, path)
+	if err != nil {
+		return "", "", err
+	}
+
+	switch {
+	case target.IsArray():
+		if !gjson.Valid(raw) {
+			return "", "", fmt.Errorf("invalid JSON: %s", raw)
+		}
+		count := len(target.Array())
+		return insertMember(doc, target, -1, raw), JoinPath(path, fmt.Sprint(count)), nil
+
+	case target.IsObject():
+		wrapped := "{" + raw + "}"
+		if !gjson.Valid(wrapped) || len(gjson.Parse(wrapped).Map()) != 1 {
+			return "", "", fmt.Errorf(`expected "key": value, got %s`, raw)
+		}
+		var key, value gjson.Result
+		gjson.Parse(wrapped).ForEach(func(k, v gjson.Result) bool {
+			key, value = k, v
+			return false
+		})
+		if target.Get(gjson.Escape(key.String())).Exists() {
+			return "", "", fmt.Errorf("key %q already exists", key.String())
+		}
+		raw = quote(key.String()) + ": " + value.Raw
+		return insertMember(doc, target, -1, raw), JoinPath(path, gjson.Escape(key.String())), nil
+	}
+	return "", "", fmt.Errorf("%s is not an object or array", path)
+}
+
+// InsertAfter inserts raw JSON after the array element at path. It returns
+// the new document and the path of the inserted element.
+func InsertAfter(doc, path, raw string) (string, string, error) {
+	m, err := locateMember(doc, path)
+	if err != nil {
+		return "", "", err
+	}
+	if !m.parent.IsArray() {
+		return "", "", fmt.Errorf("%s is not an array element", path)
+	}
+	if !gjson.Valid(raw) {
+		return "", "", fmt.Errorf("invalid JSON: %s", raw)
+	}
+	parentPath, _ := ParentPath(path)
+	return insertMember(doc, m.parent, m.index+1, raw), JoinPath(parentPath, fmt.Sprint(m.index+1)), nil
+}
+
+// insertMember inserts a member, formatted as a key and value for objects, at
+// position index of a container, or at its end if index is -1. It is indented
+// like the member before it.
+func insertMember(doc string, container gjson.Result, index int, raw string) string {
+	var prevStart, prevEnd, count int
+	container.ForEach(func(k, v gjson.Result) bool {
+		if count == index {
+			return false
+		}
+		prevStart, prevEnd = v.Index, v.Index+len(v.Raw)
+		if container.IsObject() {
+			prevStart = k.Index
+		}
+		count++
+		return true
+	})
+
+	if count == 0 {
+		open, close := container.Index, container.Index+len(container.Raw)-1
+		return doc[:open+1] + raw + doc[close:]
+	}
+	// Reuse the whitespace before the previous member, e.g. a newline and
+	// indentation. The first member of a container on one line has none, so
+	// follow the spacing after its key.
+	indent := prevStart
+	for indent > 0 && strings.ContainsRune(" \t\r\n", rune(doc[indent-1])) {
+		indent--
+	}
+	space := doc[indent:prevStart]
+	if space == "" && strings.Contains(container.Raw, ": ") {
+		space = " "
+	}
+	return doc[:prevEnd] + "," + space + raw + doc[prevEnd:]
+}
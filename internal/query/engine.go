@@ -2,6 +2,7 @@ package query
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/tidwall/gjson"
@@ -10,15 +11,27 @@ import (
 // QueryResult represents the result of a JSON path query
 type QueryResult struct {
 	Value   string // The resulting value from the query
+	Raw     string // Raw JSON of the resulting value (empty if path is invalid)
 	IsValid bool   // Whether the path was valid
 	Error   string // Error message if path is invalid
+	Failed  bool   // Whether a plugin modifier failed, rather than the path not existing
+	Pending bool   // Whether a plugin modifier is still running, see RunPluginsInBackground
+
+	Expanded []string // Paths of the JSON strings the path traversed when expanding strings
 }
 
+// Indent is the indentation of each level of pretty-printed results
+var Indent = "  "
+
 // Engine handles JSON querying with gjson and maintains state
 type Engine struct {
 	jsonData       string
 	lastValidPath  string
 	lastValidValue string
+	lastValidRaw   string
+	lastExpanded   []string // Expanded strings of the last valid path
+	expandStrings  bool
+	pluginFinished func() // Set when plugins run in the background, see RunPluginsInBackground
 }
 
 // NewEngine creates a new query engine with the provided JSON data
@@ -27,6 +40,7 @@ func NewEngine(jsonData string) *Engine {
 		jsonData:       jsonData,
 		lastValidPath:  "",
 		lastValidValue: jsonData, // Initially, empty path returns the whole document
+		lastValidRaw:   jsonData,
 	}
 }
 
@@ -39,21 +53,49 @@ func (e *Engine) Query(path string) QueryResult {
 		if err != nil {
 			return QueryResult{
 				Value:   e.jsonData,
+				Raw:     e.jsonData,
 				IsValid: true,
 				Error:   "",
 			}
 		}
 		e.lastValidPath = ""
 		e.lastValidValue = prettyJSON
+		e.lastValidRaw = e.jsonData
+		e.lastExpanded = nil
 		return QueryResult{
 			Value:   prettyJSON,
+			Raw:     e.jsonData,
 			IsValid: true,
 			Error:   "",
 		}
 	}
 
-	// Execute the gjson query
-	result := gjson.Get(e.jsonData, path)
+	// Execute the gjson query, surfacing failures of plugin modifiers
+	target, expanded := path, []string(nil)
+	var result gjson.Result
+	ev := &evaluation{engine: e}
+	ev.run(func() {
+		if e.expandStrings {
+			target, expanded = ExpandPath(e.jsonData, path)
+		}
+		result = gjson.Get(e.jsonData, target)
+	})
+	switch err := ev.err; {
+	case errors.Is(err, ErrPluginRunning):
+		return QueryResult{
+			Value:   e.lastValidValue,
+			IsValid: false,
+			Error:   fmt.Sprintf("Modifier %v", err),
+			Pending: true,
+		}
+	case err != nil:
+		return QueryResult{
+			Value:   e.lastValidValue,
+			IsValid: false,
+			Error:   fmt.Sprintf("Modifier failed: %v", err),
+			Failed:  true,
+		}
+	}
 
 	// Check if the path exists
 	if !result.Exists() {
@@ -68,28 +110,37 @@ func (e *Engine) Query(path string) QueryResult {
 	// Path is valid, update last valid state
 	e.lastValidPath = path
 
-	// Get the value as a string
-	valueStr := result.String()
-
-	// If the result is a JSON object or array, pretty print it
-	if result.IsObject() || result.IsArray() {
-		prettyJSON, err := prettyPrintJSON(result.Raw)
-		if err == nil {
-			valueStr = prettyJSON
-		} else {
-			valueStr = result.Raw
-		}
-	}
-
+	valueStr := FormatValue(result)
 	e.lastValidValue = valueStr
+	e.lastValidRaw = result.Raw
+	e.lastExpanded = expanded
 
 	return QueryResult{
-		Value:   valueStr,
-		IsValid: true,
-		Error:   "",
+		Value:    valueStr,
+		Raw:      result.Raw,
+		IsValid:  true,
+		Error:    "",
+		Expanded: expanded,
 	}
 }
 
+// SetExpandStrings sets whether paths traverse strings holding JSON objects
+// and arrays as if they were parsed, and re-runs the last valid path
+func (e *Engine) SetExpandStrings(expand bool) {
+	e.expandStrings = expand
+	if !e.Query(e.lastValidPath).IsValid {
+		e.Query("")
+	}
+}
+
+// RunPluginsInBackground makes plugin modifiers without a cached result run in
+// the background instead of blocking the query, which then fails as pending.
+// finished is called from another goroutine once such a result is cached; nil
+// runs plugins in the foreground again.
+func (e *Engine) RunPluginsInBackground(finished func()) {
+	e.pluginFinished = finished
+}
+
 // GetLastValidPath returns the last valid path that was queried
 func (e *Engine) GetLastValidPath() string {
 	return e.lastValidPath
@@ -100,6 +151,44 @@ func (e *Engine) GetLastValidValue() string {
 	return e.lastValidValue
 }
 
+// GetLastValidRaw returns the raw JSON of the last valid result
+func (e *Engine) GetLastValidRaw() string {
+	return e.lastValidRaw
+}
+
+// GetLastExpanded returns the paths of the JSON strings the last valid path
+// traversed when expanding strings
+func (e *Engine) GetLastExpanded() []string {
+	return e.lastExpanded
+}
+
+// GetData returns the JSON document being queried
+func (e *Engine) GetData() string {
+	return e.jsonData
+}
+
+// SetData replaces the JSON document being queried. The last valid result is
+// updated, or reset to the whole document if its path no longer exists.
+func (e *Engine) SetData(jsonData string) {
+	e.jsonData = jsonData
+	if !e.Query(e.lastValidPath).IsValid {
+		e.Query("")
+	}
+}
+
+// FormatValue shows a value as results are shown: objects and arrays pretty
+// printed, and other values as their string
+func FormatValue(result gjson.Result) string {
+	if !result.IsObject() && !result.IsArray() {
+		return result.String()
+	}
+	prettyJSON, err := prettyPrintJSON(result.Raw)
+	if err != nil {
+		return result.Raw
+	}
+	return prettyJSON
+}
+
 // prettyPrintJSON formats JSON with indentation
 func prettyPrintJSON(jsonStr string) (string, error) {
 	var obj any
@@ -108,7 +197,7 @@ func prettyPrintJSON(jsonStr string) (string, error) {
 		return "", err
 	}
 
-	prettyBytes, err := json.MarshalIndent(obj, "", "  ")
+	prettyBytes, err := json.MarshalIndent(obj, "", Indent)
 	if err != nil {
 		return "", err
 	}
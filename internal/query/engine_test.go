@@ -2,7 +2,11 @@ package query
 
 import (
 	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/tidwall/gjson"
 )
 
 func TestNewEngine(t *testing.T) {
@@ -282,3 +286,608 @@ func TestQueryStateTracking(t *testing.T) {
 		t.Error("State should not change for invalid query")
 	}
 }
+
+func TestQueryRawValue(t *testing.T) {
+	jsonData := `{"name": "Alice", "tags": ["a", "b"]}`
+	engine := NewEngine(jsonData)
+
+	result := engine.Query("name")
+	if result.Raw != `"Alice"` {
+		t.Errorf("Expected raw value '\"Alice\"', got '%s'", result.Raw)
+	}
+
+	result = engine.Query("tags")
+	if result.Raw != `["a", "b"]` {
+		t.Errorf("Expected raw value '[\"a\", \"b\"]', got '%s'", result.Raw)
+	}
+
+	result = engine.Query("missing")
+	if result.Raw != "" {
+		t.Errorf("Expected empty raw value for invalid path, got '%s'", result.Raw)
+	}
+}
+
+func TestQueryAll(t *testing.T) {
+	engines := []*Engine{
+		NewEngine(`{"env": "staging", "replicas": 1}`),
+		NewEngine(`{"env": "prod", "replicas": 3}`),
+		NewEngine(`{"env": "dev"}`),
+	}
+
+	results := QueryAll(engines, "replicas")
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+
+	if results[0].Value != "1" || results[1].Value != "3" {
+		t.Errorf("Unexpected values: %q, %q", results[0].Value, results[1].Value)
+	}
+
+	if results[2].IsValid {
+		t.Error("Expected path to be invalid for document without the key")
+	}
+}
+
+func TestCombineResults(t *testing.T) {
+	engines := []*Engine{
+		NewEngine(`{"env": "staging"}`),
+		NewEngine(`{"env": "prod"}`),
+		NewEngine(`{"other": true}`),
+	}
+	names := []string{"b.json", "a.json", "c.json"}
+
+	combined := CombineResults(names, QueryAll(engines, "env"))
+
+	expected := "{\n  \"b.json\": \"staging\",\n  \"a.json\": \"prod\",\n  \"c.json\": null\n}"
+	if combined != expected {
+		t.Errorf("Expected combined result:\n%s\ngot:\n%s", expected, combined)
+	}
+}
+
+func TestGetLastValidRaw(t *testing.T) {
+	jsonData := `{"name": "Alice", "tags": ["a"]}`
+	engine := NewEngine(jsonData)
+
+	if engine.GetLastValidRaw() != jsonData {
+		t.Errorf("Expected initial raw value to be the document, got '%s'", engine.GetLastValidRaw())
+	}
+
+	engine.Query("tags")
+	engine.Query("missing")
+	if engine.GetLastValidRaw() != `["a"]` {
+		t.Errorf("Expected raw value of last valid path, got '%s'", engine.GetLastValidRaw())
+	}
+}
+
+const modifierJSON = `{
+	"values": [3, 1, 2, "x", null],
+	"empty": [],
+	"orders": [
+		{"status": "paid", "total": 20},
+		{"status": "open", "total": 10},
+		{"status": "paid", "total": 30},
+		{"total": 5}
+	],
+	"config": {"db": {"host": "localhost", "ports": [5432, {"replica": 5433}], "opts": {}}},
+	"columns": {"a": [1, 2], "b": [3, 4]}
+}`
+
+func TestModifierAggregates(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"values|@sum", "6"},
+		{"values|@avg", "2"},
+		{"values|@min", "1"},
+		{"values|@max", "3"},
+		{"orders.#.total|@sum", "65"},
+		{"orders.#.total|@avg", "16.25"},
+		{"empty|@sum", "0"},
+		{"empty|@avg", "null"},
+		{"empty|@min", "null"},
+		{"values|@count", "5"},
+		{"config.db|@count", "3"},
+	}
+
+	for _, tt := range tests {
+		result := gjson.Get(modifierJSON, tt.path)
+		if result.Raw != tt.expected {
+			t.Errorf("Expected %s for %s, got %s", tt.expected, tt.path, result.Raw)
+		}
+	}
+
+	// Aggregates need an array
+	if gjson.Get(modifierJSON, "config|@sum").Exists() {
+		t.Error("Expected @sum of an object not to exist")
+	}
+}
+
+func TestModifierUnique(t *testing.T) {
+	result := gjson.Get(`[1, 1.0, "1", {"a": 1, "b": 2}, {"b": 2, "a": 1}, [1], [1]]`, "@unique")
+
+	expected := `[1,"1",{"a": 1, "b": 2},[1]]`
+	if result.Raw != expected {
+		t.Errorf("Expected %s, got %s", expected, result.Raw)
+	}
+}
+
+func TestModifierSort(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"values|@sort", `[null,1,2,3,"x"]`},
+		{`values|@sort:{"desc":true}`, `["x",3,2,1,null]`},
+		{`orders|@sort:{"by":"total"}|#.total`, `[5,10,20,30]`},
+		{`orders|@sort:{"by":"total","desc":true}|#.total`, `[30,20,10,5]`},
+		// Elements without the field sort first, ties keep their order
+		{`orders|@sort:{"by":"status"}|#.total`, `[5,10,20,30]`},
+	}
+
+	for _, tt := range tests {
+		result := gjson.Get(modifierJSON, tt.path)
+		if result.Raw != tt.expected {
+			t.Errorf("Expected %s for %s, got %s", tt.expected, tt.path, result.Raw)
+		}
+	}
+}
+
+func TestModifierGroup(t *testing.T) {
+	result := gjson.Get(modifierJSON, `orders|@group:"status"`)
+
+	if keys := result.Get("@keys").Raw; keys != `["paid","open"]` {
+		t.Errorf("Expected groups in order of first appearance, got %s", keys)
+	}
+	if totals := result.Get("paid.#.total").Raw; totals != `[20,30]` {
+		t.Errorf("Expected paid totals [20,30], got %s", totals)
+	}
+
+	// The argument may also be given without quotes
+	if gjson.Get(modifierJSON, "orders|@group:status|open.0.total").Raw != "10" {
+		t.Error("Expected unquoted field argument to group by status")
+	}
+
+	// Without an argument gjson's built-in zip behaviour is kept
+	zipped := gjson.Get(modifierJSON, "columns|@group").Raw
+	if zipped != `[{"a":1,"b":3},{"a":2,"b":4}]` {
+		t.Errorf("Expected zipped columns, got %s", zipped)
+	}
+}
+
+func TestModifierFlattenKeys(t *testing.T) {
+	result := gjson.Get(modifierJSON, "config|@flattenkeys")
+
+	expected := `{"db.host":"localhost","db.ports.0":5432,"db.ports.1.replica":5433,"db.opts":{}}`
+	if result.Raw != expected {
+		t.Errorf("Expected %s, got %s", expected, result.Raw)
+	}
+
+	result = gjson.Get(modifierJSON, `config|@flattenkeys:"/"`)
+	if !result.Get(`db/ports/1/replica`).Exists() {
+		t.Errorf("Expected keys joined with /, got %s", result.Raw)
+	}
+}
+
+func TestModifierEntries(t *testing.T) {
+	result := gjson.Get(`{"a": 1, "b": [true]}`, "@entries")
+
+	expected := `[{"key":"a","value":1},{"key":"b","value":[true]}]`
+	if result.Raw != expected {
+		t.Errorf("Expected %s, got %s", expected, result.Raw)
+	}
+
+	if gjson.Get(`[1]`, "@entries").Exists() {
+		t.Error("Expected @entries of an array not to exist")
+	}
+}
+
+func TestModifierJSON(t *testing.T) {
+	tests := []struct {
+		json     string
+		expected string
+	}{
+		{`"{\"a\": 1}"`, `{"a": 1}`},
+		{`" [1, 2] "`, `[1, 2]`},
+		{`{"a": 1}`, `{"a": 1}`},
+		{`"not json"`, `"not json"`},
+		// Like ExpandPath, only strings holding an object or array are parsed
+		{`"123"`, `"123"`},
+		{`"true"`, `"true"`},
+	}
+	for _, tt := range tests {
+		if result := gjson.Get(tt.json, "@json"); result.Raw != tt.expected {
+			t.Errorf("Expected @json of %s to be %q, got %q", tt.json, tt.expected, result.Raw)
+		}
+	}
+}
+
+func TestExpandStrings(t *testing.T) {
+	data := `{"events": [{"body": "{\"user\": {\"id\": 7}, \"tags\": \"[1, 2]\"}"}, {"body": "plain"}], "note": "{text}", "count": "123"}`
+	engine := NewEngine(data)
+
+	if engine.Query("events.0.body.user.id").IsValid {
+		t.Error("Expected paths not to traverse JSON strings by default")
+	}
+
+	engine.SetExpandStrings(true)
+	tests := []struct {
+		path     string
+		value    string
+		expanded []string
+	}{
+		{"events.0.body.user.id", "7", []string{"events.0.body"}},
+		{"events.0.body.tags.1", "2", []string{"events.0.body", "events.0.body.tags"}},
+		{"events.0.body|@json.user.id", "7", nil},
+		{"note", "{text}", nil},
+		{"count", "123", nil},
+		// After #, each element holding JSON is expanded
+		{"events.#.body.user.id|0", "7", []string{"events.#.body"}},
+		{"events.#(body!=\"plain\")#.body.user.id|0", "7", []string{"events.#(body!=\"plain\")#.body"}},
+	}
+	for _, tt := range tests {
+		result := engine.Query(tt.path)
+		if !result.IsValid || result.Value != tt.value {
+			t.Errorf("%s: expected %s, got %+v", tt.path, tt.value, result)
+		}
+		if strings.Join(result.Expanded, ",") != strings.Join(tt.expanded, ",") {
+			t.Errorf("%s: expected expanded %v, got %v", tt.path, tt.expanded, result.Expanded)
+		}
+	}
+
+	// A JSON string at the end of the path is shown parsed
+	if result := engine.Query("events.0.body"); !gjson.Parse(result.Raw).IsObject() {
+		t.Errorf("Expected the expanded body to be an object, got %s", result.Raw)
+	}
+
+	// Turning expansion off resets a path that only exists when expanded
+	engine.Query("events.0.body.user")
+	engine.SetExpandStrings(false)
+	if engine.GetLastValidPath() != "" || engine.GetLastExpanded() != nil {
+		t.Errorf("Expected the path to be reset, got %q expanding %v", engine.GetLastValidPath(), engine.GetLastExpanded())
+	}
+}
+
+func TestModifierInEngine(t *testing.T) {
+	engine := NewEngine(modifierJSON)
+
+	result := engine.Query("orders.#.total|@max")
+	if !result.IsValid || result.Value != "30" {
+		t.Errorf("Expected valid result 30, got %+v", result)
+	}
+}
+
+func TestModifiersRegistered(t *testing.T) {
+	for _, m := range Modifiers {
+		if !gjson.ModifierExists(strings.TrimPrefix(m.Name, "@"), nil) {
+			t.Errorf("Expected modifier %s to be registered", m.Name)
+		}
+	}
+}
+
+func TestPluginModifiers(t *testing.T) {
+	err := RegisterPlugins([]Plugin{
+		{Name: "testupper", Expression: "upper(value) + arg", Timeout: time.Second},
+		{Name: "testcat", Command: []string{"cat"}, Timeout: time.Second},
+		{Name: "testfail", Command: []string{"sh", "-c", "echo boom >&2; exit 3"}, Timeout: time.Second},
+		{Name: "testslow", Command: []string{"sleep", "5"}, Timeout: 100 * time.Millisecond},
+		{Name: "testtext", Command: []string{"echo", "not json"}, Timeout: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	engine := NewEngine(`{"name": "ada", "tags": ["a", "b"]}`)
+
+	result := engine.Query(`name|@testupper:!`)
+	if !result.IsValid || result.Value != "ADA!" {
+		t.Errorf("Expected expression result ADA!, got %+v", result)
+	}
+
+	result = engine.Query("tags|@testcat|1")
+	if !result.IsValid || result.Value != "b" {
+		t.Errorf("Expected command output to be queried further, got %+v", result)
+	}
+
+	tests := map[string]string{
+		"name|@testfail": "@testfail: exit status 3: boom",
+		"name|@testslow": "@testslow: timed out after 100ms",
+		"name|@testtext": "@testtext: output is not valid JSON",
+	}
+	for path, expected := range tests {
+		result := engine.Query(path)
+		if result.IsValid || !result.Failed || !strings.Contains(result.Error, expected) {
+			t.Errorf("Expected failure containing %q for %s, got %+v", expected, path, result)
+		}
+	}
+
+	// A failure does not leak into the next query
+	if result := engine.Query("name"); !result.IsValid || result.Failed {
+		t.Errorf("Expected valid result after a failed plugin, got %+v", result)
+	}
+
+	// Registered plugins are offered like other modifiers
+	found := false
+	for _, m := range Modifiers {
+		found = found || m.Name == "@testcat"
+	}
+	if !found {
+		t.Error("Expected @testcat in the modifier list")
+	}
+}
+
+func TestPluginsInBackground(t *testing.T) {
+	err := RegisterPlugins([]Plugin{
+		{Name: "testbackground", Command: []string{"sh", "-c", "sleep 0.2; cat"}, Timeout: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	finished := make(chan struct{}, 1)
+	engine := NewEngine(`{"tags": ["a", "b"]}`)
+	engine.RunPluginsInBackground(func() { finished <- struct{}{} })
+	start := time.Now()
+	result := engine.Query("tags|@testbackground|0")
+	if !result.Pending || result.IsValid || !strings.Contains(result.Error, "@testbackground: still running") {
+		t.Errorf("Expected the query to wait for the plugin, got %+v", result)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Expected the query not to block, took %s", elapsed)
+	}
+
+	// Querying again while it runs doesn't start it twice
+	engine.Query("tags|@testbackground|0")
+	<-finished
+	select {
+	case <-finished:
+		t.Error("Expected the plugin to run once")
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	if result := engine.Query("tags|@testbackground|0"); !result.IsValid || result.Value != "a" {
+		t.Errorf("Expected the cached result once the plugin finished, got %+v", result)
+	}
+}
+
+func TestPluginsPerEngine(t *testing.T) {
+	err := RegisterPlugins([]Plugin{
+		{Name: "testqueued", Command: []string{"sh", "-c", "sleep 0.2; cat"}, Timeout: time.Second},
+		{Name: "testcrash", Command: []string{"sh", "-c", "exit 1"}, Timeout: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	waiting := NewEngine(`{"tags": ["a", "b"]}`)
+	waiting.RunPluginsInBackground(func() {})
+	failing := NewEngine(`{"tags": ["a", "b"]}`)
+	plain := NewEngine(`{"tags": ["a", "b"]}`)
+
+	// Each engine sees only the outcome of its own plugins
+	var wg sync.WaitGroup
+	results := make([]QueryResult, 3)
+	for i, run := range []func() QueryResult{
+		func() QueryResult { return waiting.Query("tags|@testqueued|0") },
+		func() QueryResult { return failing.Query("tags|@testcrash") },
+		func() QueryResult { return plain.Query("tags|0") },
+	} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				results[i] = run()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !results[0].Pending || results[0].Failed {
+		t.Errorf("Expected the background engine to wait for its plugin, got %+v", results[0])
+	}
+	if !results[1].Failed || results[1].Pending {
+		t.Errorf("Expected the plugin to fail, got %+v", results[1])
+	}
+	if !results[2].IsValid || results[2].Value != "a" {
+		t.Errorf("Expected a valid result without plugins, got %+v", results[2])
+	}
+}
+
+func TestRegisterPluginsErrors(t *testing.T) {
+	if err := RegisterPlugins([]Plugin{{Name: "sum", Command: []string{"cat"}}}); err == nil {
+		t.Error("Expected error when replacing an existing modifier, got nil")
+	}
+	if err := RegisterPlugins([]Plugin{{Name: "testbroken", Expression: "value +"}}); err == nil {
+		t.Error("Expected error for an invalid expression, got nil")
+	}
+}
+
+func TestFold(t *testing.T) {
+	value := gjson.Parse(`{"a": 1, "b": {"c": [1, 2], "d": {}}, "e": [{"f": true}]}`)
+
+	expected := `{
+  "a": 1,
+  "b": {
+    "c": [… 2 items],
+    "d": {}
+  },
+  "e": [
+    {… 1 key}
+  ]
+}`
+	if folded, _ := Outline(value, map[string]bool{"b.c": true, "e.0": true}); folded != expected {
+		t.Errorf("Expected the collapsed paths folded:\n%s\ngot:\n%s", expected, folded)
+	}
+	if folded, _ := Outline(value, map[string]bool{"": true}); folded != "{… 3 keys}" {
+		t.Errorf("Expected the whole value folded, got %q", folded)
+	}
+	pretty, _ := prettyPrintJSON(value.Raw)
+	if folded, _ := Outline(value, nil); folded != pretty {
+		t.Errorf("Expected nothing collapsed to pretty-print the value, got %q", folded)
+	}
+
+	collapsible := map[string]bool{"b": true, "b.c": true, "b.d": false, "a": false, "e.0": true}
+	for path, expected := range collapsible {
+		if Collapsible(value.Get(path)) != expected {
+			t.Errorf("Expected %s collapsible to be %v", path, expected)
+		}
+	}
+}
+
+func TestOutline(t *testing.T) {
+	value, err := prettyPrintJSON(`{"a": 1, "b": {"c.d": [1, 2]}}`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	text, paths := Outline(gjson.Parse(value), nil)
+	expected := []string{"", "a", "b", `b.c\.d`, `b.c\.d.0`, `b.c\.d.1`, `b.c\.d`, "b", ""}
+	if text != value || strings.Join(paths, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected paths %q, got %q", expected, paths)
+	}
+	if len(paths) != strings.Count(text, "\n")+1 {
+		t.Errorf("Expected a path for each of the lines of %q, got %q", text, paths)
+	}
+
+	// Folded lines belong to the collapsed value
+	_, paths = Outline(gjson.Parse(value), map[string]bool{`b.c\.d`: true})
+	if strings.Join(paths, " ") != ` a b b.c\.d b ` {
+		t.Errorf("Expected paths of the folded value, got %q", paths)
+	}
+
+	// Other values are shown by their type, even a string holding JSON
+	scalars := map[string]string{
+		`"{\n\n  \"a\": 1\n}"`: "{\n\n  \"a\": 1\n}",
+		`"plain\ntext"`:        "plain\ntext",
+		`42`:                   "42",
+	}
+	for raw, expected := range scalars {
+		text, paths := Outline(gjson.Parse(raw), map[string]bool{"": true})
+		if text != expected || len(paths) != strings.Count(text, "\n")+1 || paths[0] != "" {
+			t.Errorf("Expected %s to be shown as %q with an empty path per line, got %q and %q", raw, expected, text, paths)
+		}
+	}
+}
+
+func TestJoinPath(t *testing.T) {
+	tests := []struct {
+		base, relative, expected string
+	}{
+		{"", "a.b", "a.b"},
+		{"users.0", "", "users.0"},
+		{"users.0", "name", "users.0.name"},
+		{`first\.name`, "0", `first\.name.0`},
+		{"users.#.name", "0", "users.#.name|0"},
+		{"tags|@reverse", "1", "tags|@reverse|1"},
+	}
+	for _, tt := range tests {
+		if path := JoinPath(tt.base, tt.relative); path != tt.expected {
+			t.Errorf("JoinPath(%q, %q) = %q, expected %q", tt.base, tt.relative, path, tt.expected)
+		}
+	}
+}
+
+func TestParentPath(t *testing.T) {
+	tests := []struct {
+		path, parent, last string
+	}{
+		{"name", "", "name"},
+		{"users.0.name", "users.0", "name"},
+		{`first\.name`, "", `first\.name`},
+		{`users.#(name=="a.b").age`, `users.#(name=="a.b")`, "age"},
+		{"users.#.name|0", "users.#.name", "0"},
+		{`{a.b,c}.a`, "{a.b,c}", "a"},
+	}
+	for _, tt := range tests {
+		if parent, last := ParentPath(tt.path); parent != tt.parent || last != tt.last {
+			t.Errorf("ParentPath(%q) = %q, %q, expected %q, %q", tt.path, parent, last, tt.parent, tt.last)
+		}
+	}
+}
+
+func TestSetData(t *testing.T) {
+	engine := NewEngine(`{"a": {"b": 1}}`)
+	engine.Query("a.b")
+
+	engine.SetData(`{"a": {"b": 2}}`)
+	if engine.GetLastValidValue() != "2" {
+		t.Errorf("Expected the last valid result to be updated, got %q", engine.GetLastValidValue())
+	}
+
+	engine.SetData(`{"c": 3}`)
+	if engine.GetLastValidPath() != "" || engine.GetData() != `{"c": 3}` {
+		t.Errorf("Expected the result to be reset to the document, got path %q", engine.GetLastValidPath())
+	}
+}
+
+func TestEdit(t *testing.T) {
+	doc := "{\n  \"users\": [\n    {\"name\": \"Alice\"},\n    {\"name\": \"Bob\"}\n  ],\n  \"count\": 2\n}"
+
+	tests := []struct {
+		name     string
+		edit     func() (string, error)
+		expected string
+	}{
+		{"set", func() (string, error) { return Set(doc, "count", "3") },
+			"{\n  \"users\": [\n    {\"name\": \"Alice\"},\n    {\"name\": \"Bob\"}\n  ],\n  \"count\": 3\n}"},
+		{"set root", func() (string, error) { return Set(doc, "", "[]") }, "[]"},
+		{"delete first", func() (string, error) { return Delete(doc, "users") },
+			"{\n  \"count\": 2\n}"},
+		{"delete last", func() (string, error) { return Delete(doc, "users.1") },
+			"{\n  \"users\": [\n    {\"name\": \"Alice\"}\n  ],\n  \"count\": 2\n}"},
+		{"delete only", func() (string, error) { return Delete(doc, "users.0.name") },
+			"{\n  \"users\": [\n    {},\n    {\"name\": \"Bob\"}\n  ],\n  \"count\": 2\n}"},
+		{"rename", func() (string, error) {
+			edited, _, err := Rename(doc, "count", "total")
+			return edited, err
+		}, "{\n  \"users\": [\n    {\"name\": \"Alice\"},\n    {\"name\": \"Bob\"}\n  ],\n  \"total\": 2\n}"},
+		{"append", func() (string, error) {
+			edited, _, err := Append(doc, "users", `{"name": "Carol"}`)
+			return edited, err
+		}, "{\n  \"users\": [\n    {\"name\": \"Alice\"},\n    {\"name\": \"Bob\"},\n    {\"name\": \"Carol\"}\n  ],\n  \"count\": 2\n}"},
+		{"insert after", func() (string, error) {
+			edited, _, err := InsertAfter(doc, "users.0", `null`)
+			return edited, err
+		}, "{\n  \"users\": [\n    {\"name\": \"Alice\"},\n    null,\n    {\"name\": \"Bob\"}\n  ],\n  \"count\": 2\n}"},
+		{"add key", func() (string, error) {
+			edited, _, err := Append(doc, "users.1", `"age": 30`)
+			return edited, err
+		}, "{\n  \"users\": [\n    {\"name\": \"Alice\"},\n    {\"name\": \"Bob\", \"age\": 30}\n  ],\n  \"count\": 2\n}"},
+	}
+	for _, tt := range tests {
+		edited, err := tt.edit()
+		if err != nil {
+			t.Errorf("%s: expected no error, got: %v", tt.name, err)
+		} else if edited != tt.expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", tt.name, tt.expected, edited)
+		}
+	}
+
+	if _, path, _ := InsertAfter(doc, "users.0", "1"); path != "users.1" {
+		t.Errorf("Expected the inserted element at users.1, got %q", path)
+	}
+	if _, path, _ := Rename(doc, "users.0.name", "first name"); path != "users.0.first name" {
+		t.Errorf("Expected the renamed path, got %q", path)
+	}
+
+	errors := []struct {
+		name string
+		err  error
+	}{
+		{"missing", second(Set(doc, "missing", "1"))},
+		{"invalid", second(Set(doc, "count", "{"))},
+		{"computed", second(Set(doc, "users.#.name", "1"))},
+		{"element key", third(Rename(doc, "users.0", "x"))},
+		{"existing key", third(Rename(doc, "count", "users"))},
+		{"append to scalar", third(Append(doc, "count", "1"))},
+		{"insert after member", third(InsertAfter(doc, "count", "1"))},
+		{"member without key", third(Append(doc, "users.0", "1"))},
+	}
+	for _, tt := range errors {
+		if tt.err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func second(_ string, err error) error          { return err }
+func third(_ string, _ string, err error) error { return err }
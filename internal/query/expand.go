@@ -0,0 +1,96 @@
+package query
+
+import (
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// ExpandPath rewrites a path so it traverses strings that hold a JSON object
+// or array, by applying @json to every such string the path reaches. Strings
+// followed by a modifier are left alone. After a # that maps the rest of the
+// path over the elements of an array, @json is applied to every element that
+// holds such a string. It also returns the paths of the strings that were
+// expanded, as written in path.
+func ExpandPath(jsonData, path string) (string, []string) {
+	segments := splitPath(path)
+	var b strings.Builder
+	var expanded []string
+	mapped := 0 // Levels of arrays the path maps over at this point
+	for i, segment := range segments {
+		b.WriteString(segment)
+		component := strings.TrimLeft(segment, ".|")
+		if strings.HasPrefix(segment, "|") {
+			// A pipe applies the rest of the path to the mapped result as a whole
+			mapped = 0
+		}
+		if component == "#" || strings.HasPrefix(component, "#(") && strings.HasSuffix(component, ")#") {
+			mapped++
+			continue
+		}
+		if i+1 < len(segments) && strings.HasPrefix(segments[i+1][1:], "@") {
+			continue
+		}
+
+		value := gjson.Get(jsonData, b.String())
+		switch {
+		case mapped == 0 && IsJSONString(value):
+			b.WriteString("|@json")
+		case mapped > 0 && holdsJSONString(value, mapped):
+			// A dot keeps applying it to each element rather than the array
+			b.WriteString(".@json")
+		default:
+			continue
+		}
+		expanded = append(expanded, strings.Join(segments[:i+1], ""))
+	}
+	return b.String(), expanded
+}
+
+// holdsJSONString reports whether any element of an array, nested levels
+// deep, is a string holding a JSON object or array
+func holdsJSONString(value gjson.Result, levels int) bool {
+	if levels == 0 {
+		return IsJSONString(value)
+	}
+	found := false
+	value.ForEach(func(_, element gjson.Result) bool {
+		found = holdsJSONString(element, levels-1)
+		return !found
+	})
+	return found
+}
+
+// IsJSONString reports whether a value is a string holding a JSON object or array
+func IsJSONString(value gjson.Result) bool {
+	if value.Type != gjson.String {
+		return false
+	}
+	s := strings.TrimSpace(value.Str)
+	return (strings.HasPrefix(s, "{") || strings.HasPrefix(s, "[")) && gjson.Valid(s)
+}
+
+// splitPath splits a path before every separator outside of queries,
+// multipaths and modifier arguments, so every segment after the first starts
+// with its separator
+func splitPath(path string) []string {
+	var segments []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '\\':
+			i++
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case '.', '|':
+			if depth == 0 && i > start {
+				segments = append(segments, path[start:i])
+				start = i
+			}
+		}
+	}
+	return append(segments, path[start:])
+}
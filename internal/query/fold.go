@@ -0,0 +1,111 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// Outline pretty-prints a value with the objects and arrays at the collapsed
+// paths, relative to the value, shown as a summary such as {… 3 keys}. It
+// returns the path of the value shown on every line of the text. Objects and
+// arrays are written in the order of value; other values are shown as results
+// show them, see FormatValue, with the empty path on all of their lines.
+func Outline(value gjson.Result, collapsed map[string]bool) (string, []string) {
+	if !value.IsObject() && !value.IsArray() {
+		text := FormatValue(value)
+		return text, make([]string, strings.Count(text, "\n")+1)
+	}
+
+	o := &outliner{collapsed: collapsed}
+	o.write(value, "", 0)
+	o.paths = append(o.paths, o.line)
+	return o.b.String(), o.paths
+}
+
+// Collapsible reports whether a value is an object or array with something
+// to collapse
+func Collapsible(value gjson.Result) bool {
+	if !value.IsObject() && !value.IsArray() {
+		return false
+	}
+	empty := true
+	value.ForEach(func(_, _ gjson.Result) bool {
+		empty = false
+		return false
+	})
+	return !empty
+}
+
+// outliner writes folded JSON and collects the path of every line it writes
+type outliner struct {
+	b         strings.Builder
+	paths     []string        // Paths of the finished lines
+	line      string          // Path of the line being written
+	collapsed map[string]bool // Paths of the objects and arrays shown as a summary
+}
+
+// newline starts a line showing the value at path
+func (o *outliner) newline(path string, level int) {
+	o.paths = append(o.paths, o.line)
+	o.line = path
+	o.b.WriteString("\n" + strings.Repeat(Indent, level))
+}
+
+// write writes r, found at path and nested level deep, on the current line
+// and the lines it needs below it
+func (o *outliner) write(r gjson.Result, path string, level int) {
+	if !r.IsObject() && !r.IsArray() {
+		o.b.WriteString(r.Raw)
+		return
+	}
+
+	open, close, count := "[", "]", 0
+	if r.IsObject() {
+		open, close = "{", "}"
+	}
+	r.ForEach(func(_, _ gjson.Result) bool {
+		count++
+		return true
+	})
+	switch {
+	case count == 0:
+		o.b.WriteString(open + close)
+		return
+	case o.collapsed[path]:
+		noun := "items"
+		if r.IsObject() {
+			noun = "keys"
+		}
+		if count == 1 {
+			noun = strings.TrimSuffix(noun, "s")
+		}
+		fmt.Fprintf(&o.b, "%s… %d %s%s", open, count, noun, close)
+		return
+	}
+
+	o.b.WriteString(open)
+	index := 0
+	r.ForEach(func(k, v gjson.Result) bool {
+		if index > 0 {
+			o.b.WriteString(",")
+		}
+		component := strconv.Itoa(index)
+		if r.IsObject() {
+			component = gjson.Escape(k.String())
+		}
+		child := JoinPath(path, component)
+
+		o.newline(child, level+1)
+		if r.IsObject() {
+			o.b.WriteString(k.Raw + ": ")
+		}
+		o.write(v, child, level+1)
+		index++
+		return true
+	})
+	o.newline(path, level)
+	o.b.WriteString(close)
+}
@@ -0,0 +1,450 @@
+package query
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// Modifier describes a path modifier for autocomplete and help
+type Modifier struct {
+	Name        string // Name including the leading @
+	Args        string // Example argument, empty if the modifier takes none
+	Description string
+}
+
+// Modifiers lists gjson's built-in modifiers followed by the ones dive adds
+var Modifiers = []Modifier{
+	{"@reverse", "", "Reverse array order or object keys"},
+	{"@ugly", "", "Compact JSON"},
+	{"@pretty", "", "Pretty-print JSON"},
+	{"@this", "", "Current element"},
+	{"@valid", "", "Only JSON that is valid"},
+	{"@flatten", "", "Flatten nested arrays"},
+	{"@join", "", "Join objects into one object"},
+	{"@keys", "", "Object keys as array"},
+	{"@values", "", "Object values as array"},
+	{"@tostr", "", "JSON as a string"},
+	{"@fromstr", "", "JSON from a string"},
+	{"@json", "", "JSON object or array from a string, leaving other values as they are"},
+	{"@dig", `:name`, "All values of a key at any depth"},
+	{"@sum", "", "Sum of the numbers in an array"},
+	{"@avg", "", "Mean of the numbers in an array"},
+	{"@min", "", "Smallest number in an array"},
+	{"@max", "", "Largest number in an array"},
+	{"@count", "", "Number of array elements or object keys"},
+	{"@unique", "", "Array without duplicate elements"},
+	{"@sort", `:{"by":"key","desc":true}`, "Sort an array, optionally by a path and descending"},
+	{"@group", `:"field"`, "Group array elements by the value of a field"},
+	{"@flattenkeys", `:"."`, "Flatten nested objects into dotted keys"},
+	{"@entries", "", "Object as an array of key/value pairs"},
+}
+
+func init() {
+	gjson.AddModifier("sum", modSum)
+	gjson.AddModifier("avg", modAvg)
+	gjson.AddModifier("min", modMin)
+	gjson.AddModifier("max", modMax)
+	gjson.AddModifier("count", modCount)
+	gjson.AddModifier("unique", modUnique)
+	gjson.AddModifier("sort", modSort)
+	gjson.AddModifier("group", modGroup)
+	gjson.AddModifier("flattenkeys", modFlattenKeys)
+	gjson.AddModifier("entries", modEntries)
+	gjson.AddModifier("json", modJSON)
+}
+
+// numbers returns the numeric elements of a JSON array, ignoring other values.
+// ok is false if the value is not an array.
+func numbers(jsonStr string) (nums []float64, ok bool) {
+	value := gjson.Parse(jsonStr)
+	if !value.IsArray() {
+		return nil, false
+	}
+	value.ForEach(func(_, element gjson.Result) bool {
+		if element.Type == gjson.Number {
+			nums = append(nums, element.Num)
+		}
+		return true
+	})
+	return nums, true
+}
+
+// formatNumber formats a number as JSON
+func formatNumber(v float64) string {
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return "null"
+	}
+	if math.Abs(v) >= 1e21 {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// quote encodes a string as JSON
+func quote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// modSum adds up the numbers of an array
+func modSum(jsonStr, arg string) string {
+	nums, ok := numbers(jsonStr)
+	if !ok {
+		return ""
+	}
+	sum := 0.0
+	for _, n := range nums {
+		sum += n
+	}
+	return formatNumber(sum)
+}
+
+// modAvg averages the numbers of an array, or returns null if there are none
+func modAvg(jsonStr, arg string) string {
+	nums, ok := numbers(jsonStr)
+	if !ok {
+		return ""
+	}
+	if len(nums) == 0 {
+		return "null"
+	}
+	sum := 0.0
+	for _, n := range nums {
+		sum += n
+	}
+	return formatNumber(sum / float64(len(nums)))
+}
+
+// modMin returns the smallest number of an array, or null if there are none
+func modMin(jsonStr, arg string) string {
+	return extreme(jsonStr, func(a, b float64) bool { return a < b })
+}
+
+// modMax returns the largest number of an array, or null if there are none
+func modMax(jsonStr, arg string) string {
+	return extreme(jsonStr, func(a, b float64) bool { return a > b })
+}
+
+// extreme returns the number that is better than all others
+func extreme(jsonStr string, better func(a, b float64) bool) string {
+	nums, ok := numbers(jsonStr)
+	if !ok {
+		return ""
+	}
+	if len(nums) == 0 {
+		return "null"
+	}
+	best := nums[0]
+	for _, n := range nums[1:] {
+		if better(n, best) {
+			best = n
+		}
+	}
+	return formatNumber(best)
+}
+
+// modCount returns the number of array elements or object keys
+func modCount(jsonStr, arg string) string {
+	value := gjson.Parse(jsonStr)
+	if !value.IsArray() && !value.IsObject() {
+		return ""
+	}
+	count := 0
+	value.ForEach(func(_, _ gjson.Result) bool {
+		count++
+		return true
+	})
+	return strconv.Itoa(count)
+}
+
+// modUnique removes duplicate array elements, keeping the first occurrence.
+// Objects are equal regardless of key order.
+func modUnique(jsonStr, arg string) string {
+	value := gjson.Parse(jsonStr)
+	if !value.IsArray() {
+		return ""
+	}
+
+	seen := make(map[string]bool)
+	var elements []string
+	value.ForEach(func(_, element gjson.Result) bool {
+		key := canonical(element)
+		if !seen[key] {
+			seen[key] = true
+			elements = append(elements, element.Raw)
+		}
+		return true
+	})
+	return "[" + strings.Join(elements, ",") + "]"
+}
+
+// canonical returns a representation of a value that is equal for equal values
+func canonical(value gjson.Result) string {
+	switch {
+	case value.IsObject():
+		var members []string
+		value.ForEach(func(key, child gjson.Result) bool {
+			members = append(members, quote(key.String())+":"+canonical(child))
+			return true
+		})
+		sort.Strings(members)
+		return "{" + strings.Join(members, ",") + "}"
+	case value.IsArray():
+		var elements []string
+		value.ForEach(func(_, child gjson.Result) bool {
+			elements = append(elements, canonical(child))
+			return true
+		})
+		return "[" + strings.Join(elements, ",") + "]"
+	case value.Type == gjson.Number:
+		return formatNumber(value.Num)
+	case value.Type == gjson.String:
+		return quote(value.Str)
+	}
+	return value.Raw
+}
+
+// modSort sorts an array. The optional argument {"by": path, "desc": bool}
+// sorts by the value at path within each element and in descending order.
+// Values of different types order as null, false, true, numbers, strings,
+// then arrays and objects.
+func modSort(jsonStr, arg string) string {
+	value := gjson.Parse(jsonStr)
+	if !value.IsArray() {
+		return ""
+	}
+
+	options := gjson.Parse(arg)
+	by := options.Get("by").String()
+	desc := options.Get("desc").Bool()
+
+	elements := value.Array()
+	keys := make([]gjson.Result, len(elements))
+	for i, element := range elements {
+		if by == "" {
+			keys[i] = element
+		} else {
+			keys[i] = element.Get(by)
+		}
+	}
+
+	order := make([]int, len(elements))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		c := compare(keys[order[i]], keys[order[j]])
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	raws := make([]string, len(order))
+	for i, index := range order {
+		raws[i] = elements[index].Raw
+	}
+	return "[" + strings.Join(raws, ",") + "]"
+}
+
+// typeRank orders JSON types for sorting; missing values sort first
+func typeRank(value gjson.Result) int {
+	switch {
+	case !value.Exists():
+		return 0
+	case value.Type == gjson.Null:
+		return 1
+	case value.Type == gjson.False:
+		return 2
+	case value.Type == gjson.True:
+		return 3
+	case value.Type == gjson.Number:
+		return 4
+	case value.Type == gjson.String:
+		return 5
+	}
+	return 6
+}
+
+// compare orders two values by type, then numbers numerically, strings
+// lexically and arrays and objects by their raw JSON
+func compare(a, b gjson.Result) int {
+	ra, rb := typeRank(a), typeRank(b)
+	if ra != rb {
+		return ra - rb
+	}
+
+	switch a.Type {
+	case gjson.Number:
+		switch {
+		case a.Num < b.Num:
+			return -1
+		case a.Num > b.Num:
+			return 1
+		}
+		return 0
+	case gjson.String:
+		return strings.Compare(a.Str, b.Str)
+	}
+	return strings.Compare(a.Raw, b.Raw)
+}
+
+// modGroup groups array elements into an object keyed by the value of the
+// field given as argument, e.g. @group:"status". Elements without the field
+// are left out. Without an argument it keeps gjson's built-in behaviour of
+// zipping an object of arrays into an array of objects.
+func modGroup(jsonStr, arg string) string {
+	if arg == "" {
+		return zipGroup(jsonStr)
+	}
+
+	value := gjson.Parse(jsonStr)
+	if !value.IsArray() {
+		return ""
+	}
+
+	field := arg
+	if parsed := gjson.Parse(arg); parsed.Type == gjson.String {
+		field = parsed.Str
+	}
+
+	var order []string
+	groups := make(map[string][]string)
+	value.ForEach(func(_, element gjson.Result) bool {
+		key := element.Get(field)
+		if !key.Exists() {
+			return true
+		}
+		name := key.String()
+		if _, ok := groups[name]; !ok {
+			order = append(order, name)
+		}
+		groups[name] = append(groups[name], element.Raw)
+		return true
+	})
+
+	members := make([]string, len(order))
+	for i, name := range order {
+		members[i] = quote(name) + ":[" + strings.Join(groups[name], ",") + "]"
+	}
+	return "{" + strings.Join(members, ",") + "}"
+}
+
+// zipGroup turns {"a":[1,2],"b":[3,4]} into [{"a":1,"b":3},{"a":2,"b":4}]
+func zipGroup(jsonStr string) string {
+	value := gjson.Parse(jsonStr)
+	if !value.IsObject() {
+		return ""
+	}
+
+	var rows [][]string
+	value.ForEach(func(key, column gjson.Result) bool {
+		if !column.IsArray() {
+			return true
+		}
+		i := 0
+		column.ForEach(func(_, element gjson.Result) bool {
+			if i == len(rows) {
+				rows = append(rows, nil)
+			}
+			rows[i] = append(rows[i], key.Raw+":"+element.Raw)
+			i++
+			return true
+		})
+		return true
+	})
+
+	objects := make([]string, len(rows))
+	for i, row := range rows {
+		objects[i] = "{" + strings.Join(row, ",") + "}"
+	}
+	return "[" + strings.Join(objects, ",") + "]"
+}
+
+// modFlattenKeys flattens nested objects and arrays into a single object whose
+// keys are the joined paths to each leaf. The separator defaults to "." and can
+// be given as argument, e.g. @flattenkeys:"/".
+func modFlattenKeys(jsonStr, arg string) string {
+	value := gjson.Parse(jsonStr)
+	if !value.IsObject() && !value.IsArray() {
+		return ""
+	}
+
+	separator := "."
+	if parsed := gjson.Parse(arg); parsed.Type == gjson.String {
+		separator = parsed.Str
+	} else if arg != "" {
+		separator = arg
+	}
+
+	var members []string
+	var flatten func(prefix string, value gjson.Result)
+	flatten = func(prefix string, value gjson.Result) {
+		index := 0
+		value.ForEach(func(key, child gjson.Result) bool {
+			name := key.String()
+			if value.IsArray() {
+				name = strconv.Itoa(index)
+			}
+			index++
+			if prefix != "" {
+				name = prefix + separator + name
+			}
+
+			if hasChildren(child) {
+				flatten(name, child)
+			} else {
+				// Leaves and empty containers keep their value
+				members = append(members, quote(name)+":"+child.Raw)
+			}
+			return true
+		})
+	}
+	flatten("", value)
+
+	return "{" + strings.Join(members, ",") + "}"
+}
+
+// hasChildren reports whether a value is a non-empty object or array
+func hasChildren(value gjson.Result) bool {
+	if !value.IsObject() && !value.IsArray() {
+		return false
+	}
+	found := false
+	value.ForEach(func(_, _ gjson.Result) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// modEntries turns an object into an array of {"key": ..., "value": ...} objects
+func modEntries(jsonStr, arg string) string {
+	value := gjson.Parse(jsonStr)
+	if !value.IsObject() {
+		return ""
+	}
+
+	var entries []string
+	value.ForEach(func(key, child gjson.Result) bool {
+		entries = append(entries, `{"key":`+quote(key.String())+`,"value":`+child.Raw+"}")
+		return true
+	})
+	return "[" + strings.Join(entries, ",") + "]"
+}
+
+// modJSON parses a string holding a JSON object or array, like @fromstr, but
+// passes other values, including other strings, through so paths can apply it
+// to values that may already be parsed. It expands the same strings as
+// ExpandPath.
+func modJSON(jsonStr, arg string) string {
+	if value := gjson.Parse(jsonStr); IsJSONString(value) {
+		return strings.TrimSpace(value.Str)
+	}
+	return jsonStr
+}
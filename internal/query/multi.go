@@ -0,0 +1,46 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// QueryAll executes the same path against every engine and returns the results in order
+func QueryAll(engines []*Engine, path string) []QueryResult {
+	results := make([]QueryResult, len(engines))
+	for i, engine := range engines {
+		results[i] = engine.Query(path)
+	}
+	return results
+}
+
+// CombineResults merges per-document results into a single {name: result} object.
+// Keys keep the order of names; documents where the path is invalid map to null.
+// The returned JSON is pretty printed.
+func CombineResults(names []string, results []QueryResult) string {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+
+		if i < len(results) && results[i].IsValid && results[i].Raw != "" {
+			buf.WriteString(results[i].Raw)
+		} else {
+			buf.WriteString("null")
+		}
+	}
+	buf.WriteByte('}')
+
+	// Indent in place so the document order of keys is preserved
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, buf.Bytes(), "", Indent); err != nil {
+		return buf.String()
+	}
+	return pretty.String()
+}
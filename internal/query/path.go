@@ -0,0 +1,57 @@
+package query
+
+import "strings"
+
+// JoinPath appends a relative path to the path of a result. Paths with
+// queries or modifiers are continued with a pipe so the relative path
+// applies to their result.
+func JoinPath(base, relative string) string {
+	switch {
+	case relative == "":
+		return base
+	case base == "":
+		return relative
+	case strings.ContainsAny(unescaped(base), "#@*?|(){}[]!=<>%,"):
+		return base + "|" + relative
+	}
+	return base + "." + relative
+}
+
+// unescaped removes the escaped characters of a path
+func unescaped(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' {
+			i++
+			continue
+		}
+		b.WriteByte(path[i])
+	}
+	return b.String()
+}
+
+// ParentPath returns the path of the value containing the result of path,
+// and the last component of path relative to it. Separators inside queries,
+// multipaths and modifier arguments are not split on.
+func ParentPath(path string) (parent, last string) {
+	depth := 0
+	separator := -1
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '\\':
+			i++
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case '.', '|':
+			if depth == 0 {
+				separator = i
+			}
+		}
+	}
+	if separator < 0 {
+		return "", path
+	}
+	return path[:separator], path[separator+1:]
+}
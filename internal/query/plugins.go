@@ -0,0 +1,250 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/tidwall/gjson"
+)
+
+// maxPluginCacheEntries bounds how many results are remembered per plugin
+const maxPluginCacheEntries = 128
+
+// Plugin is a user-defined modifier that transforms the current value with an
+// external command or an embedded expression
+type Plugin struct {
+	Name        string        // Modifier name without the leading @
+	Description string        // Shown in autocomplete and help
+	Command     []string      // Executable and arguments; the value is written to stdin and stdout is the result
+	Expression  string        // Expression evaluated with the decoded value as value and the modifier argument as arg
+	Timeout     time.Duration // How long the command or expression may run
+}
+
+// plugin is a registered plugin with its compiled expression and result cache
+type plugin struct {
+	Plugin
+	program *vm.Program
+
+	mu      sync.Mutex
+	cache   map[string]pluginResult
+	running map[string]map[*Engine]func() // Completion callbacks of the engines waiting for each result computed in the background
+}
+
+// pluginResult is a cached plugin outcome; failures are cached too so a slow
+// or failing command is not rerun on every keystroke
+type pluginResult struct {
+	output string
+	err    error
+}
+
+// ErrPluginRunning is the failure of a query whose plugin is still running in
+// the background, see Engine.RunPluginsInBackground
+var ErrPluginRunning = errors.New("still running")
+
+// evaluation collects the plugin outcomes of a query an engine evaluates.
+// gjson modifiers are shared by every engine and cannot return errors, so
+// queries are evaluated one at a time with their evaluation current.
+type evaluation struct {
+	engine *Engine
+	err    error // First plugin failure
+}
+
+var (
+	evaluating sync.Mutex                 // Held while a query is evaluated
+	current    atomic.Pointer[evaluation] // Evaluation of the query being evaluated, nil outside of queries
+)
+
+// run calls get with the evaluation current
+func (ev *evaluation) run(get func()) {
+	evaluating.Lock()
+	defer evaluating.Unlock()
+	current.Store(ev)
+	defer current.Store(nil)
+	get()
+}
+
+// fail records a plugin failure unless one was already recorded
+func (ev *evaluation) fail(err error) {
+	if ev.err == nil {
+		ev.err = err
+	}
+}
+
+// RegisterPlugins makes each plugin available as an @name modifier. Existing
+// modifiers cannot be replaced. Like gjson.AddModifier it must be called
+// before queries run.
+func RegisterPlugins(plugins []Plugin) error {
+	for _, definition := range plugins {
+		if gjson.ModifierExists(definition.Name, nil) {
+			return fmt.Errorf("plugin @%s: a modifier with this name already exists", definition.Name)
+		}
+
+		p := &plugin{Plugin: definition, cache: make(map[string]pluginResult), running: make(map[string]map[*Engine]func())}
+		if p.Expression != "" {
+			program, err := expr.Compile(p.Expression)
+			if err != nil {
+				return fmt.Errorf("plugin @%s: invalid expression: %w", p.Name, err)
+			}
+			p.program = program
+		}
+
+		gjson.AddModifier(p.Name, p.modify)
+		Modifiers = append(Modifiers, Modifier{Name: "@" + p.Name, Description: p.Description})
+	}
+	return nil
+}
+
+// modify is the gjson modifier function of the plugin. Its outcome goes to
+// the evaluation of the engine running the query; paths read outside of an
+// engine's query only use cached results.
+func (p *plugin) modify(jsonStr, arg string) string {
+	ev := current.Load()
+	key := arg + "\x00" + jsonStr
+
+	p.mu.Lock()
+	result, ok := p.cache[key]
+	p.mu.Unlock()
+
+	switch {
+	case ok:
+	case ev == nil:
+		return ""
+	case ev.engine.pluginFinished != nil:
+		p.runInBackground(key, jsonStr, arg, ev.engine)
+		ev.fail(fmt.Errorf("@%s: %w", p.Name, ErrPluginRunning))
+		return ""
+	default:
+		result = p.run(jsonStr, arg)
+		p.mu.Lock()
+		p.store(key, result)
+		p.mu.Unlock()
+	}
+
+	if result.err != nil {
+		if ev != nil {
+			ev.fail(fmt.Errorf("@%s: %w", p.Name, result.err))
+		}
+		return ""
+	}
+	return result.output
+}
+
+// runInBackground runs the plugin in a goroutine unless it already runs for
+// the same input, and tells the engine when the result is cached
+func (p *plugin) runInBackground(key, jsonStr, arg string, engine *Engine) {
+	p.mu.Lock()
+	waiting, running := p.running[key]
+	if !running {
+		waiting = make(map[*Engine]func())
+		p.running[key] = waiting
+	}
+	waiting[engine] = engine.pluginFinished
+	p.mu.Unlock()
+	if running {
+		return
+	}
+
+	go func() {
+		result := p.run(jsonStr, arg)
+		p.mu.Lock()
+		p.store(key, result)
+		delete(p.running, key)
+		p.mu.Unlock()
+
+		for _, finished := range waiting {
+			finished()
+		}
+	}()
+}
+
+// run evaluates the expression or runs the command within the timeout
+func (p *plugin) run(jsonStr, arg string) pluginResult {
+	ctx, cancel := context.WithTimeout(context.Background(), p.Timeout)
+	defer cancel()
+
+	var result pluginResult
+	if p.program != nil {
+		result.output, result.err = p.evaluate(ctx, jsonStr, arg)
+	} else {
+		result.output, result.err = p.execute(ctx, jsonStr, arg)
+	}
+	if result.err == nil && !gjson.Valid(result.output) {
+		result.err = fmt.Errorf("output is not valid JSON")
+	}
+	return result
+}
+
+// store caches a result, starting over when the cache is full. p.mu must be held.
+func (p *plugin) store(key string, result pluginResult) {
+	if len(p.cache) >= maxPluginCacheEntries {
+		p.cache = make(map[string]pluginResult)
+	}
+	p.cache[key] = result
+}
+
+// execute runs the command with the value on stdin, passing a non-empty
+// modifier argument as its last argument
+func (p *plugin) execute(ctx context.Context, jsonStr, arg string) (string, error) {
+	args := p.Command[1:]
+	if arg != "" {
+		args = append(args[:len(args):len(args)], arg)
+	}
+
+	cmd := exec.CommandContext(ctx, p.Command[0], args...)
+	cmd.Stdin = strings.NewReader(jsonStr)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Don't wait for children that keep the output pipes open after a timeout
+	cmd.WaitDelay = 100 * time.Millisecond
+
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return "", fmt.Errorf("timed out after %s", p.Timeout)
+	}
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("%w: %s", err, message)
+		}
+		return "", err
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// evaluate runs the expression on the decoded value and encodes its result
+func (p *plugin) evaluate(ctx context.Context, jsonStr, arg string) (string, error) {
+	var value any
+	if err := json.Unmarshal([]byte(jsonStr), &value); err != nil {
+		return "", err
+	}
+
+	done := make(chan pluginResult, 1)
+	go func() {
+		output, err := expr.Run(p.program, map[string]any{"value": value, "arg": arg})
+		if err != nil {
+			done <- pluginResult{err: err}
+			return
+		}
+		encoded, err := json.Marshal(output)
+		done <- pluginResult{output: string(encoded), err: err}
+	}()
+
+	select {
+	case result := <-done:
+		return result.output, result.err
+	case <-ctx.Done():
+		// The expression cannot be interrupted; its result is discarded
+		return "", fmt.Errorf("timed out after %s", p.Timeout)
+	}
+}
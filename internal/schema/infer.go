@@ -0,0 +1,206 @@
+package schema
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// Type is a set of JSON types observed for a value
+type Type int
+
+const (
+	Null Type = 1 << iota
+	Boolean
+	Integer
+	Number
+	String
+	Array
+	Object
+)
+
+// typeNames lists the JSON Schema names of each type in display order
+var typeNames = []struct {
+	t    Type
+	name string
+}{
+	{Object, "object"},
+	{Array, "array"},
+	{String, "string"},
+	{Integer, "integer"},
+	{Number, "number"},
+	{Boolean, "boolean"},
+	{Null, "null"},
+}
+
+// Names returns the JSON Schema type names contained in the set
+func (t Type) Names() []string {
+	// A value that was both an integer and a fractional number is a number
+	if t&Number != 0 {
+		t &^= Integer
+	}
+
+	names := []string{}
+	for _, tn := range typeNames {
+		if t&tn.t != 0 {
+			names = append(names, tn.name)
+		}
+	}
+	return names
+}
+
+// String returns the type names joined as a union, e.g. "string|null"
+func (t Type) String() string {
+	return strings.Join(t.Names(), "|")
+}
+
+const (
+	// maxTrackedStrings bounds how many distinct string values are remembered per node
+	maxTrackedStrings = 20
+	// maxEnumValues is the largest set of distinct strings reported as an enum
+	maxEnumValues = 8
+	// minEnumOccurrences is how often strings must be observed before they are considered an enum
+	minEnumOccurrences = 3
+)
+
+// Node describes the inferred shape of all values observed at one path
+type Node struct {
+	Types       Type
+	Count       int         // Number of values observed
+	ObjectCount int         // Number of objects observed, used to decide required properties
+	Properties  []*Property // Object properties in order of first appearance
+	Items       *Node       // Merged shape of all array elements
+	Min         float64     // Smallest number observed
+	Max         float64     // Largest number observed
+	ArrayCount  int         // Number of arrays observed
+	MinItems    int         // Shortest array observed
+	MaxItems    int         // Longest array observed
+
+	properties   map[string]*Property // Index of Properties by name
+	strings      map[string]int       // Occurrences of each distinct string, nil once too many were seen
+	stringsCount int                  // Number of string values observed
+}
+
+// Property is a named object member with the shape of its values
+type Property struct {
+	Name  string
+	Node  *Node
+	Count int // Number of objects containing the property
+}
+
+// Required reports whether the property was present in every object of its parent
+func (p *Property) Required(parent *Node) bool {
+	return p.Count == parent.ObjectCount
+}
+
+// Infer walks a raw JSON value and returns its inferred shape
+func Infer(raw string) *Node {
+	node := &Node{}
+	node.observe(gjson.Parse(raw))
+	return node
+}
+
+// observe merges a single value into the node
+func (n *Node) observe(value gjson.Result) {
+	n.Count++
+
+	switch {
+	case value.IsObject():
+		n.Types |= Object
+		n.ObjectCount++
+		value.ForEach(func(key, child gjson.Result) bool {
+			prop := n.property(key.String())
+			prop.Count++
+			prop.Node.observe(child)
+			return true
+		})
+	case value.IsArray():
+		n.Types |= Array
+		n.ArrayCount++
+		if n.Items == nil {
+			n.Items = &Node{}
+		}
+		length := 0
+		value.ForEach(func(_, child gjson.Result) bool {
+			n.Items.observe(child)
+			length++
+			return true
+		})
+		if n.ArrayCount == 1 || length < n.MinItems {
+			n.MinItems = length
+		}
+		n.MaxItems = max(n.MaxItems, length)
+	case value.Type == gjson.String:
+		n.Types |= String
+		n.observeString(value.Str)
+	case value.Type == gjson.Number:
+		if n.Types&(Integer|Number) == 0 {
+			n.Min, n.Max = value.Num, value.Num
+		}
+		if value.Num == math.Trunc(value.Num) && !strings.ContainsAny(value.Raw, ".eE") {
+			n.Types |= Integer
+		} else {
+			n.Types |= Number
+		}
+		n.Min = min(n.Min, value.Num)
+		n.Max = max(n.Max, value.Num)
+	case value.Type == gjson.True, value.Type == gjson.False:
+		n.Types |= Boolean
+	default:
+		n.Types |= Null
+	}
+}
+
+// observeString counts distinct strings until there are too many to be an enum
+func (n *Node) observeString(s string) {
+	n.stringsCount++
+	if n.stringsCount == 1 {
+		n.strings = make(map[string]int)
+	}
+	if n.strings == nil {
+		return
+	}
+	n.strings[s]++
+	if len(n.strings) > maxTrackedStrings {
+		n.strings = nil
+	}
+}
+
+// property returns the named property, adding it if it was not seen before
+func (n *Node) property(name string) *Property {
+	if p, ok := n.properties[name]; ok {
+		return p
+	}
+	if n.properties == nil {
+		n.properties = make(map[string]*Property)
+	}
+	p := &Property{Name: name, Node: &Node{}}
+	n.properties[name] = p
+	n.Properties = append(n.Properties, p)
+	return p
+}
+
+// Enum returns the distinct strings observed if they look like a fixed set of
+// values: few distinct strings that were each repeated. The values are returned
+// in order of descending frequency.
+func (n *Node) Enum() []string {
+	if n.Types != String || n.strings == nil || len(n.strings) > maxEnumValues ||
+		n.stringsCount < minEnumOccurrences || len(n.strings) == n.stringsCount {
+		return nil
+	}
+
+	values := make([]string, 0, len(n.strings))
+	for s := range n.strings {
+		values = append(values, s)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		ci, cj := n.strings[values[i]], n.strings[values[j]]
+		if ci != cj {
+			return ci > cj
+		}
+		return values[i] < values[j]
+	})
+	return values
+}
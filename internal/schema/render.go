@@ -0,0 +1,185 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Tree renders the node as a compact indented outline, one line per property.
+// Optional properties are marked with a trailing "?".
+func (n *Node) Tree() string {
+	var b strings.Builder
+	b.WriteString(n.describe())
+	n.writeChildren(&b, 1)
+	return b.String()
+}
+
+// writeChildren writes the properties of the node, or of its array elements
+func (n *Node) writeChildren(b *strings.Builder, depth int) {
+	target := n
+	if n.Types&Object == 0 && n.Items != nil {
+		// Show the shape of array elements directly below the array
+		target = n.Items
+	}
+
+	for _, p := range target.Properties {
+		b.WriteByte('\n')
+		b.WriteString(strings.Repeat("  ", depth))
+		b.WriteString(p.Name)
+		if !p.Required(target) {
+			b.WriteByte('?')
+		}
+		b.WriteString(": ")
+		b.WriteString(p.Node.describe())
+		p.Node.writeChildren(b, depth+1)
+	}
+}
+
+// describe returns a one-line summary of the types, ranges and enum values of the node
+func (n *Node) describe() string {
+	if n.Types == 0 {
+		return "unknown"
+	}
+
+	parts := []string{}
+	for _, name := range n.Types.Names() {
+		if name == "array" {
+			itemType := "unknown"
+			if n.Items != nil && n.Items.Types != 0 {
+				itemType = n.Items.Types.String()
+			}
+			name = fmt.Sprintf("array<%s>", itemType)
+		}
+		parts = append(parts, name)
+	}
+	desc := strings.Join(parts, " | ")
+
+	if n.Types&(Integer|Number) != 0 {
+		if n.Min == n.Max {
+			desc += " = " + formatNumber(n.Min)
+		} else {
+			desc += fmt.Sprintf(" %s..%s", formatNumber(n.Min), formatNumber(n.Max))
+		}
+	}
+	if n.Types&Array != 0 {
+		if n.MinItems == n.MaxItems {
+			desc += fmt.Sprintf(" [%d items]", n.MaxItems)
+		} else {
+			desc += fmt.Sprintf(" [%d..%d items]", n.MinItems, n.MaxItems)
+		}
+	}
+	if enum := n.Enum(); enum != nil {
+		quoted := make([]string, len(enum))
+		for i, v := range enum {
+			quoted[i] = strconv.Quote(v)
+		}
+		desc += " enum(" + strings.Join(quoted, ", ") + ")"
+	}
+
+	return desc
+}
+
+// formatNumber formats a number without a trailing fraction for integers
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// JSONSchemaDialect is the meta-schema URI of generated schemas
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema renders the node as a pretty-printed JSON Schema (draft 2020-12)
+func (n *Node) JSONSchema() string {
+	schema := n.jsonSchema()
+	schema = append(object{{"$schema", JSONSchemaDialect}}, schema...)
+
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return ""
+	}
+
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, data, "", "  "); err != nil {
+		return string(data)
+	}
+	return pretty.String()
+}
+
+// jsonSchema builds the schema keywords describing the node
+func (n *Node) jsonSchema() object {
+	schema := object{}
+	if n.Types == 0 {
+		return schema
+	}
+
+	names := n.Types.Names()
+	if len(names) == 1 {
+		schema = append(schema, member{"type", names[0]})
+	} else {
+		schema = append(schema, member{"type", names})
+	}
+
+	if n.Types&Object != 0 {
+		properties := object{}
+		required := []string{}
+		for _, p := range n.Properties {
+			properties = append(properties, member{p.Name, p.Node.jsonSchema()})
+			if p.Required(n) {
+				required = append(required, p.Name)
+			}
+		}
+		schema = append(schema, member{"properties", properties})
+		if len(required) > 0 {
+			schema = append(schema, member{"required", required})
+		}
+	}
+
+	if n.Types&Array != 0 && n.Items != nil && n.Items.Types != 0 {
+		schema = append(schema, member{"items", n.Items.jsonSchema()})
+	}
+
+	if n.Types&(Integer|Number) != 0 {
+		schema = append(schema, member{"minimum", n.Min}, member{"maximum", n.Max})
+	}
+
+	if enum := n.Enum(); enum != nil {
+		schema = append(schema, member{"enum", enum})
+	}
+
+	return schema
+}
+
+// member is a key/value pair of an object that keeps its key order when marshaled
+type member struct {
+	key   string
+	value any
+}
+
+// object is a JSON object whose members are marshaled in order
+type object []member
+
+// MarshalJSON writes the members in order
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
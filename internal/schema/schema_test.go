@@ -0,0 +1,150 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const usersJSON = `[
+	{"id": 1, "name": "Alice", "role": "admin", "score": 9.5, "tags": ["a"]},
+	{"id": 2, "name": "Bob", "role": "user", "email": null, "tags": []},
+	{"id": 3, "name": "Carol", "role": "user", "email": "c@example.com", "tags": ["b", "c"]},
+	{"id": 4, "name": "Dave", "role": "user", "score": 7}
+]`
+
+func TestInferTypes(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+	}{
+		{`"text"`, "string"},
+		{`42`, "integer"},
+		{`4.2`, "number"},
+		{`true`, "boolean"},
+		{`null`, "null"},
+		{`{}`, "object"},
+		{`[]`, "array"},
+		{`[1, 2.5]`, "array"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			node := Infer(tt.raw)
+			if got := node.Types.String(); got != tt.expected {
+				t.Errorf("Expected type %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestInferMergesArrayElements(t *testing.T) {
+	node := Infer(usersJSON)
+
+	if node.Items == nil || node.Items.ObjectCount != 4 {
+		t.Fatalf("Expected 4 merged objects, got %+v", node.Items)
+	}
+
+	props := map[string]*Property{}
+	var names []string
+	for _, p := range node.Items.Properties {
+		props[p.Name] = p
+		names = append(names, p.Name)
+	}
+
+	expectedNames := []string{"id", "name", "role", "score", "tags", "email"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("Expected properties %v, got %v", expectedNames, names)
+	}
+
+	if !props["id"].Required(node.Items) {
+		t.Error("Expected id to be required")
+	}
+	if props["email"].Required(node.Items) {
+		t.Error("Expected email to be optional")
+	}
+
+	if got := props["email"].Node.Types.String(); got != "string|null" {
+		t.Errorf("Expected email to be 'string|null', got %q", got)
+	}
+	if got := props["score"].Node.Types.String(); got != "number" {
+		t.Errorf("Expected score to widen to 'number', got %q", got)
+	}
+
+	id := props["id"].Node
+	if id.Min != 1 || id.Max != 4 {
+		t.Errorf("Expected id range 1..4, got %v..%v", id.Min, id.Max)
+	}
+
+	tags := props["tags"].Node
+	if tags.MinItems != 0 || tags.MaxItems != 2 {
+		t.Errorf("Expected tags length 0..2, got %d..%d", tags.MinItems, tags.MaxItems)
+	}
+}
+
+func TestEnum(t *testing.T) {
+	node := Infer(usersJSON).Items
+
+	for _, p := range node.Properties {
+		switch p.Name {
+		case "role":
+			if enum := p.Node.Enum(); !reflect.DeepEqual(enum, []string{"user", "admin"}) {
+				t.Errorf("Expected role enum [user admin], got %v", enum)
+			}
+		case "name":
+			if enum := p.Node.Enum(); enum != nil {
+				t.Errorf("Expected no enum for distinct names, got %v", enum)
+			}
+		}
+	}
+}
+
+func TestTree(t *testing.T) {
+	tree := Infer(usersJSON).Tree()
+
+	expectedLines := []string{
+		"array<object> [4 items]",
+		"  id: integer 1..4",
+		`  role: string enum("user", "admin")`,
+		"  tags?: array<string> [0..2 items]",
+		"  email?: string | null",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(tree, line+"\n") && !strings.HasSuffix(tree, line) {
+			t.Errorf("Expected tree to contain line %q, got:\n%s", line, tree)
+		}
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	output := Infer(`{"name": "Alice", "age": 30, "tags": ["x"]}`).JSONSchema()
+
+	var schema map[string]any
+	if err := json.Unmarshal([]byte(output), &schema); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+
+	if schema["$schema"] != JSONSchemaDialect {
+		t.Errorf("Expected draft 2020-12 dialect, got %v", schema["$schema"])
+	}
+	if schema["type"] != "object" {
+		t.Errorf("Expected object type, got %v", schema["type"])
+	}
+
+	required, _ := schema["required"].([]any)
+	if len(required) != 3 {
+		t.Errorf("Expected 3 required properties, got %v", schema["required"])
+	}
+
+	properties := schema["properties"].(map[string]any)
+	tags := properties["tags"].(map[string]any)
+	if items := tags["items"].(map[string]any); items["type"] != "string" {
+		t.Errorf("Expected string items, got %v", items)
+	}
+
+	// Properties keep document order
+	if strings.Index(output, `"name"`) > strings.Index(output, `"age"`) {
+		t.Errorf("Expected properties in document order, got:\n%s", output)
+	}
+}
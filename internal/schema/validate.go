@@ -0,0 +1,137 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/tidwall/gjson"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// Violation is a single place where a document does not satisfy a schema
+type Violation struct {
+	InstancePath string `json:"instancePath"` // RFC 6901 JSON pointer to the offending value
+	Path         string `json:"path"`         // gjson path to the offending value
+	KeywordPath  string `json:"keywordPath"`  // Location of the failing keyword within the schema
+	Message      string `json:"message"`
+}
+
+// Validator validates JSON documents against a compiled JSON Schema.
+// Draft 2020-12 is assumed unless the schema declares another draft with $schema.
+type Validator struct {
+	Name   string // Name of the schema, usually its file path
+	schema *jsonschema.Schema
+}
+
+// LoadValidator reads and compiles the JSON Schema at path
+func LoadValidator(path string) (*Validator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema: %w", err)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve schema path: %w", err)
+	}
+
+	return compile(path, "file://"+filepath.ToSlash(absPath), string(data))
+}
+
+// NewValidator compiles a JSON Schema given as a string
+func NewValidator(name, schemaJSON string) (*Validator, error) {
+	return compile(name, "mem://"+name, schemaJSON)
+}
+
+// compile compiles the schema, registering it under url so relative $refs resolve
+func compile(name, url, schemaJSON string) (*Validator, error) {
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(schemaJSON))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON in schema: %s", name)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.DefaultDraft(jsonschema.Draft2020)
+	if err := compiler.AddResource(url, doc); err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %w", name, err)
+	}
+
+	compiled, err := compiler.Compile(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema %s: %w", name, err)
+	}
+
+	return &Validator{Name: name, schema: compiled}, nil
+}
+
+// Validate checks jsonData against the schema and returns every violation,
+// ordered by instance location. An error is returned if jsonData is not valid JSON.
+func (v *Validator) Validate(jsonData string) ([]Violation, error) {
+	instance, err := jsonschema.UnmarshalJSON(strings.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	err = v.schema.Validate(instance)
+	if err == nil {
+		return []Violation{}, nil
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil, err
+	}
+
+	printer := message.NewPrinter(language.English)
+	violations := []Violation{}
+	collectViolations(validationErr, printer, &violations)
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].InstancePath < violations[j].InstancePath
+	})
+
+	return violations, nil
+}
+
+// collectViolations appends the leaf errors of the validation error tree;
+// intermediate errors only group their causes
+func collectViolations(err *jsonschema.ValidationError, printer *message.Printer, violations *[]Violation) {
+	if len(err.Causes) > 0 {
+		for _, cause := range err.Causes {
+			collectViolations(cause, printer, violations)
+		}
+		return
+	}
+
+	*violations = append(*violations, Violation{
+		InstancePath: pointer(err.InstanceLocation),
+		Path:         gjsonPath(err.InstanceLocation),
+		KeywordPath:  pointer(err.ErrorKind.KeywordPath()),
+		Message:      err.ErrorKind.LocalizedString(printer),
+	})
+}
+
+// pointer joins reference tokens into an RFC 6901 JSON pointer
+func pointer(tokens []string) string {
+	var b strings.Builder
+	for _, token := range tokens {
+		b.WriteByte('/')
+		b.WriteString(strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1"))
+	}
+	return b.String()
+}
+
+// gjsonPath joins reference tokens into an escaped gjson path
+func gjsonPath(tokens []string) string {
+	escaped := make([]string, len(tokens))
+	for i, token := range tokens {
+		escaped[i] = gjson.Escape(token)
+	}
+	return strings.Join(escaped, ".")
+}
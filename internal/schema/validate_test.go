@@ -0,0 +1,117 @@
+package schema
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const userSchema = `{
+	"type": "object",
+	"required": ["name", "age"],
+	"properties": {
+		"name": {"type": "string"},
+		"age": {"type": "integer", "minimum": 0},
+		"tags": {"type": "array", "items": {"type": "string"}}
+	}
+}`
+
+func TestValidateValidDocument(t *testing.T) {
+	v, err := NewValidator("user.json", userSchema)
+	if err != nil {
+		t.Fatalf("Expected schema to compile, got: %v", err)
+	}
+
+	violations, err := v.Validate(`{"name": "Alice", "age": 30, "tags": ["a"]}`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("Expected no violations, got %+v", violations)
+	}
+}
+
+func TestValidateReportsEveryViolation(t *testing.T) {
+	v, err := NewValidator("user.json", userSchema)
+	if err != nil {
+		t.Fatalf("Expected schema to compile, got: %v", err)
+	}
+
+	violations, err := v.Validate(`{"age": -1, "tags": ["a", 2]}`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	var paths []string
+	for _, violation := range violations {
+		paths = append(paths, violation.InstancePath+" => "+violation.Path)
+		if violation.Message == "" {
+			t.Errorf("Expected a message for violation at %s", violation.InstancePath)
+		}
+	}
+
+	expected := []string{" => ", "/age => age", "/tags/1 => tags.1"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Expected violations at %v, got %v", expected, paths)
+	}
+
+	if !strings.Contains(violations[0].Message, "name") {
+		t.Errorf("Expected missing property message to mention 'name', got %q", violations[0].Message)
+	}
+}
+
+func TestValidateDraft07(t *testing.T) {
+	v, err := NewValidator("draft7.json", `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"items": [{"type": "string"}],
+		"additionalItems": false
+	}`)
+	if err != nil {
+		t.Fatalf("Expected draft-07 schema to compile, got: %v", err)
+	}
+
+	violations, err := v.Validate(`["a", "b"]`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if len(violations) != 1 {
+		t.Errorf("Expected 1 violation for additional item, got %+v", violations)
+	}
+}
+
+func TestLoadValidator(t *testing.T) {
+	tempDir := t.TempDir()
+
+	schemaPath := filepath.Join(tempDir, "schema.json")
+	if err := os.WriteFile(schemaPath, []byte(userSchema), 0644); err != nil {
+		t.Fatalf("Failed to create schema file: %v", err)
+	}
+	if _, err := LoadValidator(schemaPath); err != nil {
+		t.Errorf("Expected schema file to load, got: %v", err)
+	}
+
+	if _, err := LoadValidator(filepath.Join(tempDir, "missing.json")); err == nil {
+		t.Error("Expected error for missing schema file")
+	}
+
+	invalidPath := filepath.Join(tempDir, "invalid.json")
+	if err := os.WriteFile(invalidPath, []byte(`{"type": 5}`), 0644); err != nil {
+		t.Fatalf("Failed to create schema file: %v", err)
+	}
+	if _, err := LoadValidator(invalidPath); err == nil {
+		t.Error("Expected error for schema with invalid keyword value")
+	}
+}
+
+func TestValidateInvalidJSON(t *testing.T) {
+	v, err := NewValidator("user.json", userSchema)
+	if err != nil {
+		t.Fatalf("Expected schema to compile, got: %v", err)
+	}
+
+	if _, err := v.Validate(`{invalid`); err == nil {
+		t.Error("Expected error for invalid JSON document")
+	}
+}
@@ -0,0 +1,123 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// minBarWidth is the narrowest bar drawn regardless of the available width
+const minBarWidth = 10
+
+// Render formats a summary as text, drawing histograms and frequencies as
+// bars that fit within width columns
+func Render(s *Summary, width int) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "count    %d\n", s.Count)
+	if s.Nulls > 0 {
+		fmt.Fprintf(&b, "nulls    %d\n", s.Nulls)
+	}
+	if s.Other > 0 {
+		fmt.Fprintf(&b, "other    %d (objects and arrays)\n", s.Other)
+	}
+
+	if n := s.Numeric; n != nil {
+		fmt.Fprintf(&b, "\nnumbers  %d\n", n.Count)
+		fmt.Fprintf(&b, "sum      %s\n", formatNumber(n.Sum))
+		fmt.Fprintf(&b, "min      %s\n", formatNumber(n.Min))
+		fmt.Fprintf(&b, "max      %s\n", formatNumber(n.Max))
+		fmt.Fprintf(&b, "mean     %s\n", formatNumber(n.Mean))
+		fmt.Fprintf(&b, "median   %s\n", formatNumber(n.Median))
+		fmt.Fprintf(&b, "stddev   %s\n", formatNumber(n.StdDev))
+		for _, p := range n.Percentiles {
+			fmt.Fprintf(&b, "%-8s %s\n", "p"+formatNumber(p.P), formatNumber(p.Value))
+		}
+
+		b.WriteString("\nhistogram\n")
+		labels := make([]string, len(n.Histogram))
+		counts := make([]int, len(n.Histogram))
+		for i, bin := range n.Histogram {
+			labels[i] = binLabel(bin, n.integers)
+			counts[i] = bin.Count
+		}
+		writeBars(&b, labels, counts, width)
+	}
+
+	if c := s.Categorical; c != nil {
+		fmt.Fprintf(&b, "\nstrings  %d\n", c.Count)
+		fmt.Fprintf(&b, "distinct %d\n", c.Distinct)
+
+		fmt.Fprintf(&b, "\ntop %d\n", len(c.Top))
+		labels := make([]string, len(c.Top))
+		counts := make([]int, len(c.Top))
+		for i, f := range c.Top {
+			labels[i] = strconv.Quote(f.Value)
+			counts[i] = f.Count
+		}
+		writeBars(&b, labels, counts, width)
+	}
+
+	return b.String()
+}
+
+// writeBars writes one labelled bar per count, scaled to the largest count
+func writeBars(b *strings.Builder, labels []string, counts []int, width int) {
+	labelWidth, countWidth, maxCount := 0, 0, 0
+	for i, label := range labels {
+		labelWidth = max(labelWidth, utf8.RuneCountInString(label))
+		countWidth = max(countWidth, len(strconv.Itoa(counts[i])))
+		maxCount = max(maxCount, counts[i])
+	}
+
+	// Long labels are truncated so the bars keep a usable width
+	labelWidth = min(labelWidth, max(width/3, 8))
+	barWidth := max(width-labelWidth-countWidth-2, minBarWidth)
+
+	for i, label := range labels {
+		length := 0
+		if maxCount > 0 {
+			length = int(math.Round(float64(counts[i]) / float64(maxCount) * float64(barWidth)))
+		}
+		if counts[i] > 0 && length == 0 {
+			// Keep non-empty bins visible
+			length = 1
+		}
+		fmt.Fprintf(b, "%s %s%s %*d\n",
+			pad(truncate(label, labelWidth), labelWidth),
+			strings.Repeat("█", length), strings.Repeat(" ", barWidth-length),
+			countWidth, counts[i])
+	}
+}
+
+// binLabel describes the range of a histogram bin
+func binLabel(bin Bin, integers bool) string {
+	if integers && (bin.High-bin.Low == 1 || bin.High == bin.Low) {
+		return formatNumber(bin.Low)
+	}
+	return formatNumber(bin.Low) + "–" + formatNumber(bin.High)
+}
+
+// formatNumber formats a number with at most four decimals
+func formatNumber(v float64) string {
+	if math.Abs(v) >= 1e15 {
+		return strconv.FormatFloat(v, 'g', 6, 64)
+	}
+	return strconv.FormatFloat(math.Round(v*1e4)/1e4, 'f', -1, 64)
+}
+
+// truncate shortens s to at most width runes, marking the cut with an ellipsis
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + "…"
+}
+
+// pad right-pads s with spaces to width runes
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-utf8.RuneCountInString(s), 0))
+}
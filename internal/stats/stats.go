@@ -0,0 +1,223 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// DefaultBins is the largest number of histogram bins
+	DefaultBins = 10
+	// DefaultTopN is the number of most frequent strings reported
+	DefaultTopN = 10
+	// checkInterval is how many elements are processed between cancellation checks
+	checkInterval = 4096
+)
+
+// percentiles are reported in addition to the median
+var percentiles = []float64{25, 75, 90, 95, 99}
+
+// Summary describes the elements of an array, or the values of an object
+type Summary struct {
+	Count       int          `json:"count"`
+	Nulls       int          `json:"nulls"`
+	Other       int          `json:"other"` // Objects and arrays, which are not summarized
+	Numeric     *Numeric     `json:"numeric,omitempty"`
+	Categorical *Categorical `json:"categorical,omitempty"`
+}
+
+// Numeric summarizes the numbers among the elements
+type Numeric struct {
+	Count       int          `json:"count"`
+	Sum         float64      `json:"sum"`
+	Min         float64      `json:"min"`
+	Max         float64      `json:"max"`
+	Mean        float64      `json:"mean"`
+	Median      float64      `json:"median"`
+	StdDev      float64      `json:"stddev"`
+	Percentiles []Percentile `json:"percentiles"`
+	Histogram   []Bin        `json:"histogram"`
+	integers    bool         // Whether every number is an integer, which gives integer bins
+}
+
+// Percentile is the value below which P percent of the numbers fall
+type Percentile struct {
+	P     float64 `json:"p"`
+	Value float64 `json:"value"`
+}
+
+// Bin counts the numbers in [Low, High); the last bin also includes High
+type Bin struct {
+	Low   float64 `json:"low"`
+	High  float64 `json:"high"`
+	Count int     `json:"count"`
+}
+
+// Categorical summarizes the strings and booleans among the elements
+type Categorical struct {
+	Count    int         `json:"count"`
+	Distinct int         `json:"distinct"`
+	Top      []Frequency `json:"top"` // Most frequent values, most frequent first
+}
+
+// Frequency is how often a value occurs
+type Frequency struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Compute summarizes the elements of a raw JSON array, or the values of an
+// object. It stops early with the context's error if ctx is cancelled.
+func Compute(ctx context.Context, raw string) (*Summary, error) {
+	value := gjson.Parse(raw)
+	if !value.IsArray() && !value.IsObject() {
+		return nil, fmt.Errorf("statistics need an array or object, got %s", describe(value))
+	}
+
+	summary := &Summary{}
+	var numbers []float64
+	counts := make(map[string]int)
+	categoricalCount := 0
+	integers := true
+
+	var err error
+	value.ForEach(func(_, element gjson.Result) bool {
+		summary.Count++
+		if summary.Count%checkInterval == 0 {
+			if err = ctx.Err(); err != nil {
+				return false
+			}
+		}
+
+		switch element.Type {
+		case gjson.Number:
+			numbers = append(numbers, element.Num)
+			integers = integers && element.Num == math.Trunc(element.Num)
+		case gjson.String, gjson.True, gjson.False:
+			categoricalCount++
+			counts[element.String()]++
+		case gjson.Null:
+			summary.Nulls++
+		default:
+			summary.Other++
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(numbers) > 0 {
+		summary.Numeric = numeric(numbers, integers)
+	}
+	if categoricalCount > 0 {
+		summary.Categorical = categorical(categoricalCount, counts)
+	}
+
+	return summary, nil
+}
+
+// describe names the JSON type of a value for error messages
+func describe(value gjson.Result) string {
+	switch value.Type {
+	case gjson.String:
+		return "a string"
+	case gjson.Number:
+		return "a number"
+	case gjson.True, gjson.False:
+		return "a boolean"
+	case gjson.Null:
+		return "null"
+	}
+	return "nothing"
+}
+
+// numeric computes descriptive statistics of numbers
+func numeric(numbers []float64, integers bool) *Numeric {
+	sort.Float64s(numbers)
+
+	n := &Numeric{
+		Count:    len(numbers),
+		Min:      numbers[0],
+		Max:      numbers[len(numbers)-1],
+		Median:   percentile(numbers, 50),
+		integers: integers,
+	}
+
+	for _, v := range numbers {
+		n.Sum += v
+	}
+	n.Mean = n.Sum / float64(n.Count)
+
+	variance := 0.0
+	for _, v := range numbers {
+		variance += (v - n.Mean) * (v - n.Mean)
+	}
+	n.StdDev = math.Sqrt(variance / float64(n.Count))
+
+	for _, p := range percentiles {
+		n.Percentiles = append(n.Percentiles, Percentile{P: p, Value: percentile(numbers, p)})
+	}
+
+	n.Histogram = histogram(numbers, integers, DefaultBins)
+	return n
+}
+
+// percentile interpolates linearly between the closest ranks of sorted numbers
+func percentile(sorted []float64, p float64) float64 {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// histogram counts sorted numbers in at most maxBins bins of equal width.
+// Integers spanning fewer values than maxBins get one bin per value.
+func histogram(sorted []float64, integers bool, maxBins int) []Bin {
+	low, high := sorted[0], sorted[len(sorted)-1]
+	if low == high {
+		return []Bin{{Low: low, High: high, Count: len(sorted)}}
+	}
+
+	bins := maxBins
+	width := (high - low) / float64(bins)
+	if integers && high-low+1 <= float64(maxBins) {
+		bins = int(high-low) + 1
+		width = 1
+	}
+
+	histogram := make([]Bin, bins)
+	for i := range histogram {
+		histogram[i].Low = low + float64(i)*width
+		histogram[i].High = low + float64(i+1)*width
+	}
+	for _, v := range sorted {
+		i := min(int((v-low)/width), bins-1)
+		histogram[i].Count++
+	}
+	return histogram
+}
+
+// categorical computes distinct values and the most frequent ones
+func categorical(total int, counts map[string]int) *Categorical {
+	top := make([]Frequency, 0, len(counts))
+	for value, count := range counts {
+		top = append(top, Frequency{Value: value, Count: count})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].Value < top[j].Value
+	})
+
+	return &Categorical{
+		Count:    total,
+		Distinct: len(counts),
+		Top:      top[:min(len(top), DefaultTopN)],
+	}
+}
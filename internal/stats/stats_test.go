@@ -0,0 +1,139 @@
+package stats
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestComputeNumeric(t *testing.T) {
+	summary, err := Compute(context.Background(), `[4, 1, 3, 2, 5, null, {"a": 1}]`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if summary.Count != 7 || summary.Nulls != 1 || summary.Other != 1 {
+		t.Errorf("Expected count 7, 1 null and 1 other, got %+v", summary)
+	}
+	if summary.Categorical != nil {
+		t.Errorf("Expected no categorical stats, got %+v", summary.Categorical)
+	}
+
+	n := summary.Numeric
+	if n == nil {
+		t.Fatal("Expected numeric stats, got nil")
+	}
+	if n.Count != 5 || n.Sum != 15 || n.Min != 1 || n.Max != 5 || n.Mean != 3 || n.Median != 3 {
+		t.Errorf("Expected count 5, sum 15, min 1, max 5, mean 3, median 3, got %+v", n)
+	}
+	if n.Percentiles[0].P != 25 || n.Percentiles[0].Value != 2 {
+		t.Errorf("Expected p25 of 2, got %+v", n.Percentiles[0])
+	}
+
+	// Integers spanning fewer values than the bin limit get one bin per value
+	if len(n.Histogram) != 5 {
+		t.Fatalf("Expected 5 histogram bins, got %d", len(n.Histogram))
+	}
+	for i, bin := range n.Histogram {
+		if bin.Low != float64(i+1) || bin.Count != 1 {
+			t.Errorf("Expected bin %d to start at %d with 1 value, got %+v", i, i+1, bin)
+		}
+	}
+}
+
+func TestPercentileInterpolation(t *testing.T) {
+	sorted := []float64{10, 20, 30, 40}
+
+	if got := percentile(sorted, 50); got != 25 {
+		t.Errorf("Expected median 25, got %v", got)
+	}
+	if got := percentile(sorted, 100); got != 40 {
+		t.Errorf("Expected p100 40, got %v", got)
+	}
+	if got := percentile([]float64{7}, 90); got != 7 {
+		t.Errorf("Expected p90 of a single value to be 7, got %v", got)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	bins := histogram([]float64{0, 0.5, 1, 9.5, 10}, false, 10)
+
+	if len(bins) != 10 {
+		t.Fatalf("Expected 10 bins, got %d", len(bins))
+	}
+	if bins[0].Count != 2 || bins[1].Count != 1 || bins[9].Count != 2 {
+		t.Errorf("Expected counts 2, 1, ..., 2 with the maximum in the last bin, got %+v", bins)
+	}
+
+	bins = histogram([]float64{3, 3}, true, 10)
+	if len(bins) != 1 || bins[0].Count != 2 {
+		t.Errorf("Expected a single bin for equal values, got %+v", bins)
+	}
+}
+
+func TestComputeCategorical(t *testing.T) {
+	summary, err := Compute(context.Background(), `["paid", "open", "paid", "void", "paid", "open", true]`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	c := summary.Categorical
+	if c == nil {
+		t.Fatal("Expected categorical stats, got nil")
+	}
+	if c.Count != 7 || c.Distinct != 4 {
+		t.Errorf("Expected 7 values with 4 distinct, got %+v", c)
+	}
+
+	expected := []Frequency{{"paid", 3}, {"open", 2}, {"true", 1}, {"void", 1}}
+	for i, f := range expected {
+		if c.Top[i] != f {
+			t.Errorf("Expected top %d to be %+v, got %+v", i, f, c.Top[i])
+		}
+	}
+}
+
+func TestComputeObjectValues(t *testing.T) {
+	summary, err := Compute(context.Background(), `{"a": 1, "b": 3}`)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if summary.Numeric == nil || summary.Numeric.Mean != 2 {
+		t.Errorf("Expected mean of object values to be 2, got %+v", summary.Numeric)
+	}
+}
+
+func TestComputeErrors(t *testing.T) {
+	if _, err := Compute(context.Background(), `"text"`); err == nil || !strings.Contains(err.Error(), "a string") {
+		t.Errorf("Expected error for a string, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	large := "[" + strings.Repeat("1,", checkInterval) + "1]"
+	if _, err := Compute(ctx, large); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRender(t *testing.T) {
+	summary, _ := Compute(context.Background(), `[1, 2, 2, 3, 3, 3, "a", "b", "a"]`)
+	text := Render(summary, 40)
+
+	expected := []string{
+		"count    9\n",
+		"numbers  6\n",
+		"sum      14\n",
+		"mean     2.3333\n",
+		"p90      3\n",
+		"1 " + strings.Repeat("█", 12) + strings.Repeat(" ", 24) + " 1\n",
+		"3 " + strings.Repeat("█", 36) + " 3\n",
+		"distinct 2\n",
+		`"a" ` + strings.Repeat("█", 34) + " 2\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(text, e) {
+			t.Errorf("Expected rendered stats to contain %q, got:\n%s", e, text)
+		}
+	}
+}
@@ -0,0 +1,471 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// keyContext is where the keys of an action are active
+type keyContext int
+
+const (
+	contextGlobal     keyContext = iota // Anywhere, before the focused component sees the key
+	contextInput                        // The input fields
+	contextDropdown                     // The autocomplete dropdown
+	contextOutput                       // The output panels
+	contextViolations                   // The violations list
+	contextHelp                         // The help panel
+	contextPalette                      // The filter of the command palette
+	contextHistory                      // The lists of the history popup
+	contextPipe                         // The output of a command the result was piped through
+)
+
+// keyContexts lists the contexts in the order they are described in the help panel
+var keyContexts = []keyContext{contextGlobal, contextInput, contextDropdown, contextOutput, contextViolations, contextHelp, contextPalette, contextHistory, contextPipe}
+
+// String returns the heading of the context in the help panel
+func (c keyContext) String() string {
+	switch c {
+	case contextInput:
+		return "Input field"
+	case contextDropdown:
+		return "Autocomplete dropdown"
+	case contextOutput:
+		return "Output panel"
+	case contextViolations:
+		return "Violations list"
+	case contextHelp:
+		return "Help panel"
+	case contextPalette:
+		return "Command palette"
+	case contextHistory:
+		return "History popup"
+	case contextPipe:
+		return "Command output"
+	default:
+		return "Anywhere"
+	}
+}
+
+// typed reports whether characters typed in the context must reach the input field
+func (c keyContext) typed() bool {
+	return c == contextGlobal || c == contextInput || c == contextPalette
+}
+
+// action is a named command that keys can be bound to
+type action struct {
+	name        string            // Name in the [keys] section of the configuration file
+	description string            // Shown in the help panel
+	keys        []string          // Default keys
+	vimKeys     []string          // Default keys in vim mode, if they differ
+	contexts    []keyContext      // Where the keys are active
+	footer      string            // Label in the footer, empty if the action is not shown there
+	palette     bool              // Whether the command palette offers the action although it isn't global
+	available   func(a *App) bool // Whether the footer shows the action; nil means always
+	run         func(a *App) bool // Performs the action; false lets the key through
+}
+
+// defaultKeys returns the keys bound to the action unless configured otherwise
+func (act *action) defaultKeys(vim bool) []string {
+	if vim && act.vimKeys != nil {
+		return act.vimKeys
+	}
+	return act.keys
+}
+
+// always wraps a method that handles every key it is bound to
+func always(f func(a *App)) func(a *App) bool {
+	return func(a *App) bool {
+		f(a)
+		return true
+	}
+}
+
+// multipleDocuments reports whether several documents are open
+func multipleDocuments(a *App) bool {
+	return len(a.documents) > 1
+}
+
+// inDiffMode reports whether the structural diff is shown
+func inDiffMode(a *App) bool {
+	return a.diffMode
+}
+
+// notDiffMode reports whether query results are shown
+func notDiffMode(a *App) bool {
+	return !a.diffMode
+}
+
+// registry returns every action in the order of the footer and help panel
+func registry() []*action {
+	global := []keyContext{contextGlobal}
+	input := []keyContext{contextInput}
+	dropdown := []keyContext{contextDropdown}
+	output := []keyContext{contextOutput}
+
+	return []*action{
+		{name: "autocomplete", description: "Show suggestions for the path", keys: []string{"tab"}, contexts: input, footer: "Autocomplete", available: notDiffMode,
+			run: always((*App).autocomplete)},
+		{name: "hide_suggestions", description: "Hide the suggestions; in vim mode, focus the output if they are hidden", keys: []string{"esc"}, contexts: []keyContext{contextInput, contextDropdown},
+			run: always(func(a *App) {
+				if a.vim && !a.dropdownVisible {
+					a.focusOutput()
+					return
+				}
+				a.hideDropdown()
+			})},
+		{name: "record_history", description: "Add the path to the query history", keys: []string{"enter"}, contexts: input,
+			run: (*App).recordHistory},
+		{name: "history_previous", description: "Select a suggestion, or show the previous query", keys: []string{"up"}, contexts: input,
+			run: (*App).historyPrevious},
+		{name: "history_next", description: "Select a suggestion, or show the next query", keys: []string{"down"}, contexts: input,
+			run: (*App).historyNext},
+
+		{name: "next_document", description: "Switch to the next document", keys: []string{"ctrl+n"}, contexts: global, footer: "Next Doc", available: multipleDocuments,
+			run: always((*App).nextDocument)},
+		{name: "previous_document", description: "Switch to the previous document", keys: []string{"ctrl+b"}, contexts: global, footer: "Prev Doc", available: multipleDocuments,
+			run: always((*App).previousDocument)},
+		{name: "back", description: "Go back to the previous path visited", keys: []string{"alt+,"}, contexts: global, available: notDiffMode,
+			run: always(func(a *App) { a.goToVisit(-1) })},
+		{name: "forward", description: "Go forward to the next path visited", keys: []string{"alt+."}, contexts: global, available: notDiffMode,
+			run: always(func(a *App) { a.goToVisit(1) })},
+		{name: "undo", description: "Undo the last edit of the path", keys: []string{"ctrl+z"}, contexts: global,
+			run: always((*App).undo)},
+		{name: "redo", description: "Redo the last undone edit of the path", keys: []string{"ctrl+y"}, contexts: global,
+			run: always((*App).redo)},
+		{name: "history", description: "List the visited paths and edits", keys: []string{"ctrl+r"}, contexts: global, footer: "History",
+			run: always((*App).toggleHistory)},
+		{name: "parent_path", description: "Go up to the parent of the current path", keys: []string{"alt+up"}, contexts: global, available: notDiffMode,
+			run: always((*App).drillUp)},
+		{name: "root_path", description: "Go to the root of the document", keys: []string{"alt+home"}, contexts: global, available: notDiffMode,
+			run: always((*App).goToRoot)},
+		{name: "next_sibling", description: "Go to the next key or array element", keys: []string{"alt+right"}, contexts: global, available: notDiffMode,
+			run: always(func(a *App) { a.goToSibling(1) })},
+		{name: "previous_sibling", description: "Go to the previous key or array element", keys: []string{"alt+left"}, contexts: global, available: notDiffMode,
+			run: always(func(a *App) { a.goToSibling(-1) })},
+		{name: "compare_mode", description: "Cycle compare mode (off, side by side, combined)", keys: []string{"f4"}, contexts: global, footer: "Compare",
+			available: func(a *App) bool { return multipleDocuments(a) && !a.diffMode },
+			run: func(a *App) bool {
+				if !a.diffMode && !a.split.visible {
+					a.cycleCompareMode()
+				}
+				return true
+			}},
+		{name: "export_patch", description: "Export the diff as a JSON Patch", keys: []string{"ctrl+e"}, contexts: global, footer: "Export Patch", available: inDiffMode,
+			run: func(a *App) bool {
+				if !a.diffMode {
+					return false
+				}
+				a.showSaveDialogFor(" Export JSON Patch ", "patch.json", a.diffPatch)
+				return true
+			}},
+		{name: "help", description: "Show or hide the help panel", keys: []string{"f1"}, contexts: global, footer: "Help",
+			run: always((*App).toggleHelpPanel)},
+		{name: "command_palette", description: "Show or hide the command palette", keys: []string{"ctrl+p"}, contexts: global, footer: "Commands",
+			run: always((*App).togglePalette)},
+		{name: "focus_output", description: "Focus the output to scroll it", keys: []string{"ctrl+o"}, contexts: global, footer: "Focus Output", available: notDiffMode,
+			run: always((*App).focusOutput)},
+		{name: "copy", description: "Copy the output to the clipboard", keys: []string{"alt+c"}, contexts: global, footer: "Copy", available: notDiffMode,
+			run: always((*App).copyToClipboard)},
+		{name: "save", description: "Save the output to a file", keys: []string{"ctrl+s"}, contexts: global, footer: "Save", available: notDiffMode,
+			run: always((*App).showSaveDialog)},
+		{name: "quit", description: "Quit", keys: []string{"ctrl+q", "ctrl+c"}, contexts: global, footer: "Quit",
+			run: always((*App).quit)},
+		{name: "split_pane", description: "Show or hide the split pane", keys: []string{"f2"}, contexts: global,
+			run: always((*App).toggleSplitPane)},
+		{name: "switch_pane", description: "Move focus between the main and the split pane", keys: []string{"ctrl+t"}, contexts: global,
+			run: always((*App).switchPane)},
+		{name: "highlight_differences", description: "Highlight lines that differ between the split results", keys: []string{"f3"}, contexts: global,
+			run: always((*App).toggleHighlightDifferences)},
+		{name: "sync_scroll", description: "Scroll the split results together", keys: []string{"f5"}, contexts: global,
+			run: always((*App).toggleSyncScroll)},
+		{name: "schema_view", description: "Show or hide the inferred schema", keys: []string{"f6"}, contexts: global,
+			run: always((*App).toggleSchemaView)},
+		{name: "validate", description: "Validate against a JSON Schema", keys: []string{"f7"}, contexts: global,
+			run: always((*App).toggleViolationsPanel)},
+		{name: "code_preview", description: "Cycle the code preview (Go, TypeScript, Python, off)", keys: []string{"f8"}, contexts: global,
+			run: always((*App).cycleCodeView)},
+		{name: "stats", description: "Show or hide the statistics panel", keys: []string{"f9"}, contexts: global,
+			run: always((*App).toggleStatsPanel)},
+		{name: "cycle_theme", description: "Switch to the next color theme", keys: []string{"f10"}, contexts: global,
+			run: always((*App).cycleTheme)},
+		{name: "inspector", description: "Show or hide decoded forms of the value under the cursor", keys: []string{"f12"}, contexts: global,
+			run: always((*App).toggleInspector)},
+		{name: "expand_strings", description: "Let paths traverse strings that hold JSON", keys: []string{"ctrl+g"}, contexts: global,
+			run: always((*App).toggleExpandStrings)},
+		{name: "edit_mode", description: "Turn edit mode on or off", keys: []string{"f11"}, contexts: global, available: notDiffMode,
+			run: always((*App).toggleEditMode)},
+
+		{name: "next_suggestion", description: "Select the next suggestion", keys: []string{"tab"}, contexts: dropdown,
+			run: always(func(a *App) { a.moveSuggestion(1) })},
+		{name: "previous_suggestion", description: "Select the previous suggestion", keys: []string{"shift+tab"}, contexts: dropdown,
+			run: always(func(a *App) { a.moveSuggestion(-1) })},
+		{name: "leave_suggestions", description: "Return to the input field from the first suggestion", keys: []string{"up"}, contexts: dropdown,
+			run: (*App).leaveSuggestions},
+
+		{name: "next_change", description: "Jump to the next change of the diff", keys: []string{"n"}, vimKeys: []string{"] c"}, palette: true, contexts: output, footer: "Next Change", available: inDiffMode,
+			run: func(a *App) bool {
+				if a.diffMode {
+					a.selectChange(a.diffSelected + 1)
+				}
+				return a.diffMode
+			}},
+		{name: "previous_change", description: "Jump to the previous change of the diff", keys: []string{"shift+n", "p"}, vimKeys: []string{"[ c"}, palette: true, contexts: output, footer: "Prev Change", available: inDiffMode,
+			run: func(a *App) bool {
+				if a.diffMode {
+					a.selectChange(a.diffSelected - 1)
+				}
+				return a.diffMode
+			}},
+		{name: "focus_input", description: "Return to the input field", keys: []string{"esc", "i", "shift+i"}, contexts: []keyContext{contextOutput, contextViolations},
+			run: always((*App).focusInput)},
+		{name: "scroll_down", description: "Move the cursor down a line", keys: []string{"down"}, vimKeys: []string{"j", "down"}, contexts: output,
+			run: always(func(a *App) { a.scrollLines(1) })},
+		{name: "scroll_up", description: "Move the cursor up a line", keys: []string{"up"}, vimKeys: []string{"k", "up"}, contexts: output,
+			run: always(func(a *App) { a.scrollLines(-1) })},
+		{name: "half_page_down", description: "Move down half a page", vimKeys: []string{"ctrl+d"}, contexts: output,
+			run: always(func(a *App) { a.scrollHalfPage(1) })},
+		{name: "half_page_up", description: "Move up half a page", vimKeys: []string{"ctrl+u"}, contexts: output,
+			run: always(func(a *App) { a.scrollHalfPage(-1) })},
+		{name: "scroll_top", description: "Move to the top", vimKeys: []string{"g g"}, contexts: output,
+			run: always((*App).scrollToTop)},
+		{name: "scroll_bottom", description: "Move to the bottom", vimKeys: []string{"shift+g"}, contexts: output,
+			run: always((*App).scrollToBottom)},
+		{name: "drill_down", description: "Set the path to the value under the cursor", keys: []string{"enter"}, contexts: output,
+			run: always((*App).drillDown)},
+		{name: "drill_up", description: "Set the path to the parent of the current path", keys: []string{"backspace"}, contexts: output,
+			available: notDiffMode, run: always((*App).drillUp)},
+		{name: "search", description: "Search the output", vimKeys: []string{"/"}, palette: true, contexts: output,
+			run: always((*App).promptSearch)},
+		{name: "search_next", description: "Jump to the next match", vimKeys: []string{"n"}, palette: true, contexts: output,
+			run: always(func(a *App) { a.findNext(1) })},
+		{name: "search_previous", description: "Jump to the previous match", vimKeys: []string{"shift+n"}, palette: true, contexts: output,
+			run: always(func(a *App) { a.findNext(-1) })},
+		{name: "yank_value", description: "Copy the value under the cursor", vimKeys: []string{"y y"}, palette: true, contexts: output,
+			run: always((*App).yankValue)},
+		{name: "yank_path", description: "Copy the path under the cursor", vimKeys: []string{"y p"}, palette: true, contexts: output,
+			run: always((*App).yankPath)},
+		{name: "fold", description: "Fold the object or array under the cursor", vimKeys: []string{"z c"}, palette: true, contexts: output,
+			run: always((*App).fold)},
+		{name: "unfold", description: "Unfold the object or array under the cursor", vimKeys: []string{"z o"}, palette: true, contexts: output,
+			run: always((*App).unfold)},
+		{name: "unfold_all", description: "Unfold everything", vimKeys: []string{"z shift+r"}, palette: true, contexts: output,
+			run: always((*App).unfoldAll)},
+		{name: "command", description: "Run a command: w [file], q, q!, wq, set [no]wrap, colorscheme <theme>, <line>", vimKeys: []string{":"}, palette: true, contexts: output,
+			run: always((*App).promptCommand)},
+		{name: "edit_value", description: "Replace the value under the cursor", keys: []string{"e"}, palette: true, contexts: output,
+			run: editing((*App).editValue)},
+		{name: "edit_in_editor", description: "Edit the current result in $VISUAL or $EDITOR", keys: []string{"shift+e"}, palette: true, contexts: output,
+			run: editing((*App).editInEditor)},
+		{name: "rename_key", description: "Rename the key under the cursor", keys: []string{"r"}, palette: true, contexts: output,
+			run: editing((*App).renameKey)},
+		{name: "append_value", description: "Add a value to the object or array under the cursor", keys: []string{"a"}, palette: true, contexts: output,
+			run: editing((*App).appendValue)},
+		{name: "insert_value", description: "Insert a value after the array element under the cursor", keys: []string{"shift+o"}, palette: true, contexts: output,
+			run: editing((*App).insertValue)},
+		{name: "delete_value", description: "Delete the value under the cursor", keys: []string{"d"}, vimKeys: []string{"d d"}, palette: true, contexts: output,
+			run: editing((*App).deleteValue)},
+		{name: "undo_change", description: "Undo the last change of the document", keys: []string{"u"}, palette: true, contexts: output,
+			run: editing((*App).undoChange)},
+		{name: "redo_change", description: "Redo the last undone change of the document", keys: []string{"shift+u"}, palette: true, contexts: output,
+			run: editing((*App).redoChange)},
+		{name: "save_document", description: "Save the document to its file", keys: []string{"w"}, palette: true, contexts: output,
+			run: editing((*App).saveDocument)},
+		{name: "save_document_as", description: "Save the document to another file", keys: []string{"shift+w"}, palette: true, contexts: output,
+			run: editing((*App).saveDocumentAs)},
+		{name: "explore_decoded", description: "Explore JSON decoded from the value under the cursor", keys: []string{"x"}, palette: true, contexts: output,
+			run: always((*App).exploreDecoded)},
+		{name: "pipe", description: "Pipe the result through a shell command", keys: []string{"|"}, palette: true, contexts: output,
+			run: always((*App).promptPipe)},
+		{name: "load_schema", description: "Load another JSON Schema", keys: []string{"o"}, palette: true, contexts: []keyContext{contextViolations},
+			run: always((*App).promptSchema)},
+		{name: "palette_next", description: "Select the next command", keys: []string{"down", "tab"}, contexts: []keyContext{contextPalette},
+			run: always(func(a *App) { a.movePaletteSelection(1) })},
+		{name: "palette_previous", description: "Select the previous command", keys: []string{"up", "shift+tab"}, contexts: []keyContext{contextPalette},
+			run: always(func(a *App) { a.movePaletteSelection(-1) })},
+		{name: "run_command", description: "Run the selected command", keys: []string{"enter"}, contexts: []keyContext{contextPalette},
+			run: always((*App).runPaletteEntry)},
+		{name: "close_palette", description: "Close the command palette", keys: []string{"esc"}, contexts: []keyContext{contextPalette},
+			run: always((*App).hidePalette)},
+		{name: "switch_history_list", description: "Switch between visited paths and edits", keys: []string{"tab", "shift+tab"}, contexts: []keyContext{contextHistory},
+			run: always((*App).switchHistoryList)},
+		{name: "close_history", description: "Close the history popup", keys: []string{"esc"}, contexts: []keyContext{contextHistory},
+			run: always((*App).hideHistory)},
+		{name: "open_pipe_output", description: "Open the command output as a new document", keys: []string{"enter"}, contexts: []keyContext{contextPipe},
+			run: always((*App).openPipeOutput)},
+		{name: "close_pipe", description: "Stop the command and close its output", keys: []string{"esc"}, contexts: []keyContext{contextPipe},
+			run: always((*App).closePipe)},
+		{name: "close_help", description: "Close the help panel", keys: []string{"esc"}, contexts: []keyContext{contextHelp},
+			run: always((*App).hideHelpPanel)},
+	}
+}
+
+// dispatch runs the action bound to a key in a context. The key is consumed
+// unless the action let it through. Keys that start a sequence are consumed
+// until the sequence is complete; a key that doesn't continue it cancels it.
+func (a *App) dispatch(context keyContext, event *tcell.EventKey) *tcell.EventKey {
+	node := a.keys.bound[context]
+	pending := a.pendingKeys != nil && a.pendingContext == context
+	if pending {
+		node, a.pendingKeys = a.pendingKeys, nil
+	}
+
+	next := node.next[eventBinding(event)]
+	switch {
+	case next == nil && pending:
+		return nil
+	case next == nil:
+		return event
+	case next.action == nil:
+		a.pendingKeys, a.pendingContext = next, context
+		return nil
+	case next.action.run(a):
+		return nil
+	}
+	return event
+}
+
+// captureKeys returns an input capture function dispatching keys in a context
+func (a *App) captureKeys(context keyContext) func(event *tcell.EventKey) *tcell.EventKey {
+	return func(event *tcell.EventKey) *tcell.EventKey {
+		return a.dispatch(context, event)
+	}
+}
+
+// footerText lists the bound actions that are available in the current mode.
+// Keys of the output panel are shown after the key that focuses it.
+func (a *App) footerText() string {
+	var hints []string
+	for _, act := range a.keys.actions {
+		label := a.keys.label(act.name)
+		if act.footer == "" || label == "" || (act.available != nil && !act.available(a)) {
+			continue
+		}
+
+		hint := fmt.Sprintf("[%s::b]%s[-::-]: %s", a.theme.TextKey, tview.Escape(label), act.footer)
+		if act.contexts[0] == contextOutput {
+			if focus := a.keys.label("focus_output"); focus != "" {
+				hint = fmt.Sprintf("[%s::b]%s[-::-] then %s", a.theme.TextKey, tview.Escape(focus), hint)
+			}
+		}
+		// Hints are regions so clicking them runs the action
+		hints = append(hints, fmt.Sprintf(`["%s"]%s[""]`, act.name, hint))
+	}
+	text := strings.Join(hints, " | ")
+	doc := a.currentDocument()
+	if doc.compression != nil {
+		text += fmt.Sprintf(" | [%s]%s[-]", a.theme.TextMuted, tview.Escape(doc.compression.String()))
+	}
+	if len(doc.relaxed) > 0 {
+		text += fmt.Sprintf(" | [%s]Relaxed: %s[-]", a.theme.TextMuted, strings.Join(doc.relaxed, ", "))
+	}
+	return text
+}
+
+// keysHelp describes every bound action for the help panel, grouped by context
+func (a *App) keysHelp() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s::b]Keyboard Shortcuts[::-]\n", a.theme.TextKey)
+	for _, context := range keyContexts {
+		fmt.Fprintf(&b, "\n[%s]%s:[-]\n", a.theme.TextMuted, context)
+		for _, act := range a.keys.actions {
+			label := a.keys.label(act.name)
+			if label == "" || !act.activeIn(context) {
+				continue
+			}
+			fmt.Fprintf(&b, "  %-18s%s\n", tview.Escape(label), act.description)
+		}
+	}
+	return b.String()
+}
+
+// activeIn reports whether the keys of the action are active in a context
+func (act *action) activeIn(context keyContext) bool {
+	for _, c := range act.contexts {
+		if c == context {
+			return true
+		}
+	}
+	return false
+}
+
+// helpText returns the contents of the help panel
+func (a *App) helpText() string {
+	return a.keysHelp() + "\n\n" + getHelpContent(a.theme)
+}
+
+// focusedInput returns the input field of the pane used last
+func (a *App) focusedInput() *tview.InputField {
+	if a.split.visible && a.split.active {
+		return a.split.input
+	}
+	return a.inputField
+}
+
+// autocomplete shows suggestions for the focused input field and focuses them
+func (a *App) autocomplete() {
+	a.updateSuggestionsFor(a.focusedInput())
+	if a.dropdownVisible {
+		a.tviewApp.SetFocus(a.autocompleteDropdown)
+	}
+}
+
+// recordHistory remembers the path of the main input field in the document's history
+func (a *App) recordHistory() bool {
+	if a.focusedInput() != a.inputField {
+		return false
+	}
+	doc := a.currentDocument()
+	doc.recordHistory(a.inputField.GetText())
+	doc.visits.visit(doc.queryEngine.GetLastValidPath())
+	return true
+}
+
+// historyPrevious focuses the suggestions if they are shown, otherwise it
+// moves back through the query history of the main input field
+func (a *App) historyPrevious() bool {
+	return a.browseHistory(a.currentDocument().previousHistory)
+}
+
+// historyNext focuses the suggestions if they are shown, otherwise it moves
+// forward through the query history of the main input field
+func (a *App) historyNext() bool {
+	return a.browseHistory(a.currentDocument().nextHistory)
+}
+
+// browseHistory focuses the suggestions or sets the main input field to the query returned by step
+func (a *App) browseHistory(step func() (string, bool)) bool {
+	if a.dropdownVisible {
+		a.tviewApp.SetFocus(a.autocompleteDropdown)
+		return true
+	}
+	if a.focusedInput() != a.inputField {
+		return false
+	}
+	if q, ok := step(); ok {
+		a.inputField.SetText(q)
+	}
+	return true
+}
+
+// moveSuggestion selects the suggestion offset items away, wrapping around at either end
+func (a *App) moveSuggestion(offset int) {
+	count := a.autocompleteDropdown.GetItemCount()
+	if count == 0 {
+		return
+	}
+	current := a.autocompleteDropdown.GetCurrentItem()
+	a.autocompleteDropdown.SetCurrentItem(((current+offset)%count + count) % count)
+}
+
+// leaveSuggestions returns focus to the input field when the first suggestion is selected
+func (a *App) leaveSuggestions() bool {
+	if a.autocompleteDropdown.GetCurrentItem() != 0 {
+		return false
+	}
+	a.tviewApp.SetFocus(a.dropdownTarget)
+	return true
+}
@@ -5,8 +5,12 @@ import (
 	"time"
 
 	"github.com/gataky/dive/internal/autocomplete"
+	"github.com/gataky/dive/internal/codegen"
+	"github.com/gataky/dive/internal/config"
+	"github.com/gataky/dive/internal/diff"
 	"github.com/gataky/dive/internal/export"
 	"github.com/gataky/dive/internal/query"
+	"github.com/gataky/dive/internal/schema"
 	"github.com/gataky/dive/internal/ui/theme"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -21,21 +25,38 @@ const (
 	FocusDropdown
 	FocusOutputPanel
 	FocusHelpPanel
+	FocusSplitInput
+	FocusSplitOutput
+	FocusViolations
+	FocusPipe
 )
 
-func init() {
-	tview.Borders.HorizontalFocus = tview.Borders.Horizontal
-	tview.Borders.VerticalFocus = tview.Borders.Vertical
-	tview.Borders.TopLeftFocus = tview.Borders.TopLeft
-	tview.Borders.TopRightFocus = tview.Borders.TopRight
-	tview.Borders.BottomLeftFocus = tview.Borders.BottomLeft
-	tview.Borders.BottomRightFocus = tview.Borders.BottomRight
+// OutputView selects how the output panel presents the current result
+type OutputView int
+
+const (
+	ViewResult OutputView = iota // The result itself
+	ViewSchema                   // Its inferred schema
+	ViewCode                     // Type definitions generated from it
+)
+
+// String returns the name of the view used in messages
+func (v OutputView) String() string {
+	switch v {
+	case ViewSchema:
+		return "schema view"
+	case ViewCode:
+		return "code preview"
+	default:
+		return "result view"
+	}
 }
 
 // App represents the main application UI
 type App struct {
 	tviewApp             *tview.Application
 	layout               *tview.Flex
+	tabBar               *tview.TextView
 	inputField           *tview.InputField
 	outputPanel          *tview.TextView
 	footer               *tview.TextView
@@ -43,62 +64,160 @@ type App struct {
 	helpPanel            *tview.TextView
 	saveModal            *tview.InputField
 	theme                *theme.Theme
+	themeName            string
+	themeOverrides       map[string]string
+	keys                 *keymap
+	pendingKeys          *keyNode   // Keys of an unfinished sequence
+	pendingContext       keyContext // Context of the unfinished sequence
+	vim                  bool
+	search               string          // Last pattern searched for in vim mode
+	collapsed            map[string]bool // Paths of the values of the result folded in vim mode, relative to the result
+	linePaths            []string        // Path of every line of the result, relative to the result
+	outputLines          []string        // Lines of the result as shown, without the cursor
+	cursor               int             // Line of the result under the cursor
+	cursorShown          bool            // Whether the cursor line is highlighted
+	statusLine           *tview.TextView
+	breadcrumbs          *tview.TextView
+	wrap                 bool
+	saveFilename         string
+	dropdownHeight       int
+	defaultLanguage      codegen.Language
 	focusedComponent     FocusableComponent
-	jsonData             string
-	currentQuery         string
-	queryEngine          *query.Engine
+	documents            []*documentState
+	activeDocument       int
+	compareMode          CompareMode
+	compareViews         []*tview.TextView
+	diffMode             bool
+	diffOptions          diff.Options
+	diffChanges          []diff.Change
+	diffSelected         int
+	split                splitPane
+	outputView           OutputView
+	schemaNode           *schema.Node
+	codeLanguage         codegen.Language
+	validator            *schema.Validator
+	violationsList       *tview.List
+	violationsVisible    bool
+	stats                statsPanel
+	palette              commandPalette
+	history              historyPopup
+	pipe                 pipePane
+	inspector            inspectorPane
+	pipeTimeout          time.Duration
+	expandStrings        bool // Whether paths traverse strings holding JSON
+	queryPending         bool // Whether the result waits for a plugin running in the background
+	pluginsInBackground  bool // Whether plugins run off the event loop, set once the application runs
+	restoring            bool // Whether undo or redo is setting the text of the input field
+	editMode             bool
+	quitWarned           bool // Whether quitting was refused once because of unsaved changes
 	dropdownVisible      bool
+	dropdownTarget       *tview.InputField
 	helpPanelVisible     bool
 	focusBeforeHelp      FocusableComponent
 	originalFooterText   string
 }
 
 // NewApp creates and initializes a new tview application with all UI components
-func NewApp(jsonData string) *App {
+// using the default configuration. Each document is opened in its own tab; at
+// least one document is required.
+func NewApp(documents []Document) *App {
+	// The default configuration is always valid
+	app, _ := NewAppWithConfig(documents, config.Default())
+	return app
+}
+
+// NewAppWithConfig creates the application with the theme, key bindings and
+// defaults of cfg
+func NewAppWithConfig(documents []Document, cfg *config.Config) (*App, error) {
+	themeName := cfg.Defaults.Theme
+	if themeName == "" {
+		themeName = theme.Names[0]
+	}
+	th, err := newTheme(themeName, cfg.Theme)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := newKeymap(cfg.Keys, cfg.Defaults.Vim)
+	if err != nil {
+		return nil, err
+	}
+	language, err := codegen.ParseLanguage(cfg.Defaults.Language)
+	if err != nil {
+		return nil, fmt.Errorf("defaults.language: %w", err)
+	}
+
 	app := &App{
-		tviewApp:           tview.NewApplication(),
-		theme:              theme.DefaultTheme(),
-		jsonData:           jsonData,
-		queryEngine:        query.NewEngine(jsonData),
-		originalFooterText: "[white::b]Tab[::-]: Autocomplete | [white::b]F1[::-]: Help | [white::b]Ctrl+O[::-]: Focus Output | [white::b]Ctrl+C[::-]: Copy | [white::b]Ctrl+S[::-]: Save | [white::b]Ctrl+Q[::-]: Quit",
+		tviewApp:        tview.NewApplication().EnableMouse(cfg.Defaults.Mouse),
+		theme:           th,
+		themeName:       themeName,
+		themeOverrides:  cfg.Theme,
+		keys:            keys,
+		vim:             cfg.Defaults.Vim,
+		wrap:            cfg.Defaults.Wrap,
+		saveFilename:    cfg.Defaults.SaveFilename,
+		dropdownHeight:  cfg.Defaults.DropdownHeight,
+		pipeTimeout:     cfg.Defaults.PipeTimeout,
+		expandStrings:   cfg.Defaults.ExpandStrings,
+		collapsed:       make(map[string]bool),
+		codeLanguage:    language,
+		defaultLanguage: language,
 	}
 
+	for _, doc := range documents {
+		app.addDocument(doc)
+	}
+	app.originalFooterText = app.footerText()
+
+	setGlobalStyles(app.theme)
 	app.initComponents()
 	app.setupLayout()
 	app.setupKeyBindings()
-	app.setupInputFieldKeyBindings()
-	app.setupOutputPanelKeyBindings()
-	app.setupHelpPanelKeyBindings()
+	app.setupMouse()
 	app.setupQueryCallbacks()
 	app.setupFocusHandlers()
 
 	// Set the json data so it shows up on startup
-	result := app.queryEngine.Query(jsonData)
-	app.outputPanel.SetText(result.Value)
+	app.runQuery("")
+	app.updateTabBar()
+	app.footer.SetText(app.originalFooterText)
 
 	// Set initial focus state to match the initially focused component
 	app.focusedComponent = FocusInputField
 	app.inputField.SetBorderColor(app.theme.BorderFocused)
 
-	return app
+	return app, nil
 }
 
 // initComponents initializes all UI components
 func (a *App) initComponents() {
+	a.tabBar = createTabBar(a.theme)
 	a.inputField = createInputField(a.theme)
-	a.outputPanel = createOutputPanel(a.theme)
+	a.outputPanel = a.newOutputPanel()
 	a.footer = createFooter(a.theme)
+	a.statusLine = createBar(a.theme)
+	a.breadcrumbs = createBar(a.theme)
 	a.autocompleteDropdown = createAutocompleteDropdown(a.theme)
-	a.helpPanel = createHelpPanel(a.theme)
+	a.helpPanel = createHelpPanel(a.theme, a.helpText())
+	a.violationsList = createViolationsList(a.theme)
+	a.dropdownTarget = a.inputField
+	a.stats.view = createStatsPanel(a.theme)
+	a.initSplitPane()
+	a.initPalette()
+	a.initHistoryPopup()
+	a.initPipePane()
+	a.inspector.view = a.newOutputPanel()
+	a.setupViolationsPanel()
+}
+
+// newOutputPanel creates an output panel that wraps lines as configured
+func (a *App) newOutputPanel() *tview.TextView {
+	return createOutputPanel(a.theme).SetWrap(a.wrap)
 }
 
 // setupLayout arranges all components in a vertical flex layout
 func (a *App) setupLayout() {
-	a.layout = tview.NewFlex().
-		SetDirection(tview.FlexRow).
-		AddItem(a.inputField, 3, 0, true).
-		AddItem(a.outputPanel, 0, 1, false).
-		AddItem(a.footer, 1, 0, false)
+	a.layout = tview.NewFlex()
+	a.rebuildLayout()
 
 	a.tviewApp.SetRoot(a.layout, true)
 
@@ -106,6 +225,88 @@ func (a *App) setupLayout() {
 	a.tviewApp.SetFocus(a.inputField)
 }
 
+// rebuildLayout rearranges the components according to the current view state:
+// the tab bar is shown when several documents are open or in edit mode, the
+// dropdown below the input field when visible, and the help panel on the right
+// side when toggled
+func (a *App) rebuildLayout() {
+	mainContent := tview.NewFlex().
+		SetDirection(tview.FlexRow)
+
+	if len(a.documents) > 1 || a.editMode {
+		mainContent.AddItem(a.tabBar, 1, 0, false)
+	}
+
+	mainPane := a.paneLayout(a.inputField, a.outputArea())
+	panes := tview.NewFlex().
+		SetDirection(tview.FlexColumn).
+		AddItem(mainPane, 0, 1, true)
+	if a.split.visible {
+		// Two input field/output panel pairs next to each other, sized by dragging the divider
+		panes.ResizeItem(mainPane, 0, a.split.ratio)
+		panes.AddItem(a.paneLayout(a.split.input, a.split.output), 0, 100-a.split.ratio, false)
+	}
+	a.split.panes, a.split.mainPane = panes, mainPane
+	if a.stats.visible {
+		panes.AddItem(a.stats.view, statsPanelWidth, 0, false)
+	}
+	mainContent.AddItem(panes, 0, 1, true)
+	if a.inspector.visible {
+		mainContent.AddItem(a.inspector.view, 0, 1, false)
+	}
+	if a.pipe.visible {
+		mainContent.AddItem(a.pipe.view, 0, 1, false)
+	}
+	if a.violationsVisible {
+		mainContent.AddItem(a.violationsList, 8, 0, false)
+	}
+	mainContent.AddItem(a.footer, 1, 0, false)
+
+	a.layout.Clear()
+	if a.helpPanelVisible {
+		// Horizontal split (50/50) with main content on left, help panel on right
+		a.layout.SetDirection(tview.FlexColumn)
+		a.layout.AddItem(mainContent, 0, 1, true)
+		a.layout.AddItem(a.helpPanel, 0, 1, false)
+	} else {
+		a.layout.SetDirection(tview.FlexRow)
+		a.layout.AddItem(mainContent, 0, 1, true)
+	}
+}
+
+// paneLayout stacks an input field above its output, with the autocomplete
+// dropdown in between when it was opened for that input field
+func (a *App) paneLayout(input *tview.InputField, output tview.Primitive) *tview.Flex {
+	pane := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(input, 3, 0, true)
+	if a.dropdownVisible && a.dropdownTarget == input {
+		pane.AddItem(a.autocompleteDropdown, a.dropdownHeight, 0, false)
+	}
+	if input == a.inputField {
+		pane.AddItem(a.breadcrumbs, 1, 0, false)
+	}
+	pane.AddItem(output, 0, 1, false)
+	if output == a.outputPanel {
+		pane.AddItem(a.statusLine, 1, 0, false)
+	}
+	return pane
+}
+
+// outputArea returns the primitive showing query results, which is a row of
+// per-document panels in side-by-side compare mode
+func (a *App) outputArea() tview.Primitive {
+	if a.compareMode != CompareSideBySide {
+		return a.outputPanel
+	}
+
+	views := tview.NewFlex().SetDirection(tview.FlexColumn)
+	for _, view := range a.compareViews {
+		views.AddItem(view, 0, 1, false)
+	}
+	return views
+}
+
 // showDropdown displays the autocomplete dropdown below the input field
 func (a *App) showDropdown(suggestions []string) {
 	if len(suggestions) == 0 {
@@ -126,57 +327,16 @@ func (a *App) showDropdown(suggestions []string) {
 		})
 	}
 
-	// Set up input capture for the dropdown to handle Escape and navigation
-	a.autocompleteDropdown.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		currentItem := a.autocompleteDropdown.GetCurrentItem()
-		itemCount := a.autocompleteDropdown.GetItemCount()
-
-		switch event.Key() {
-		case tcell.KeyEscape:
-			// Hide dropdown and return focus to input field
-			a.hideDropdown()
-			return nil
-		case tcell.KeyTab:
-			// Move to next item, wrap to top if at bottom
-			if currentItem < itemCount-1 {
-				a.autocompleteDropdown.SetCurrentItem(currentItem + 1)
-			} else {
-				a.autocompleteDropdown.SetCurrentItem(0)
-			}
-			return nil
-		case tcell.KeyBacktab:
-			// Move to previous item (Shift+Tab), wrap to bottom if at top
-			if currentItem > 0 {
-				a.autocompleteDropdown.SetCurrentItem(currentItem - 1)
-			} else {
-				a.autocompleteDropdown.SetCurrentItem(itemCount - 1)
-			}
-			return nil
-		case tcell.KeyUp:
-			// If at the top of the list, return to input field
-			if currentItem == 0 {
-				a.tviewApp.SetFocus(a.inputField)
-				return nil
-			}
-		}
-		return event
-	})
-
 	// Update layout to show dropdown if not already visible
 	if !a.dropdownVisible {
 		a.dropdownVisible = true
-		// Rebuild layout with dropdown
-		a.layout.Clear()
-		a.layout.AddItem(a.inputField, 3, 0, true)
-		a.layout.AddItem(a.autocompleteDropdown, 8, 0, false) // Show dropdown with height of 8
-		a.layout.AddItem(a.outputPanel, 0, 1, false)
-		a.layout.AddItem(a.footer, 1, 0, false)
+		a.rebuildLayout()
 	}
 }
 
 // selectSuggestion updates the input field with the selected suggestion
 func (a *App) selectSuggestion(suggestion string) {
-	a.inputField.SetText(suggestion)
+	a.dropdownTarget.SetText(suggestion)
 	a.hideDropdown()
 }
 
@@ -187,124 +347,90 @@ func (a *App) hideDropdown() {
 	}
 
 	a.dropdownVisible = false
-	// Rebuild layout without dropdown
-	a.layout.Clear()
-	a.layout.AddItem(a.inputField, 3, 0, true)
-	a.layout.AddItem(a.outputPanel, 0, 1, false)
-	a.layout.AddItem(a.footer, 1, 0, false)
+	a.rebuildLayout()
 
 	// Restore focus to input field
-	a.tviewApp.SetFocus(a.inputField)
+	a.tviewApp.SetFocus(a.dropdownTarget)
 }
 
 // updateSuggestions gets autocomplete suggestions for the current path and shows dropdown
 func (a *App) updateSuggestions() {
-	currentPath := a.inputField.GetText()
-	suggestions := autocomplete.GetSuggestions(a.jsonData, currentPath)
-	a.showDropdown(suggestions)
+	a.updateSuggestionsFor(a.inputField)
 }
 
-// setupKeyBindings configures global key bindings
-func (a *App) setupKeyBindings() {
-	a.tviewApp.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Key() {
-		case tcell.KeyCtrlQ:
-			// Quit the application
-			a.tviewApp.Stop()
-			return nil
-		case tcell.KeyCtrlC:
-			// Copy current output to clipboard (task 6.7)
-			a.copyToClipboard()
-			return nil
-		case tcell.KeyCtrlS:
-			// Open save dialog (task 6.8)
-			a.showSaveDialog()
-			return nil
-		case tcell.KeyF1:
-			// Toggle help panel
-			a.toggleHelpPanel()
-			return nil
-		case tcell.KeyCtrlO:
-			// Focus on output panel for scrolling
-			a.tviewApp.SetFocus(a.outputPanel)
-			return nil
-		}
-		return event
-	})
+// updateSuggestionsFor shows the autocomplete dropdown below input with suggestions for its path
+func (a *App) updateSuggestionsFor(input *tview.InputField) {
+	if a.dropdownVisible && a.dropdownTarget != input {
+		a.hideDropdown()
+	}
+	a.dropdownTarget = input
+
+	currentPath := input.GetText()
+	var suggestions []string
+	if a.expandStrings {
+		suggestions = autocomplete.GetExpandedSuggestions(a.currentDocument().jsonData, currentPath)
+	} else {
+		suggestions = autocomplete.GetSuggestions(a.currentDocument().jsonData, currentPath)
+	}
+	a.showDropdown(suggestions)
 }
 
-// setupInputFieldKeyBindings configures key bindings for the input field
-func (a *App) setupInputFieldKeyBindings() {
-	a.inputField.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Key() {
-		case tcell.KeyTab:
-			// Handle Tab key press to show autocomplete dropdown (task 5.9)
-			a.updateSuggestions()
-			// Automatically focus on the dropdown if it has suggestions
-			if a.dropdownVisible {
-				a.tviewApp.SetFocus(a.autocompleteDropdown)
-			}
-			return nil // Consume the Tab key event
-		case tcell.KeyEscape:
-			// Hide dropdown when Escape is pressed
-			a.hideDropdown()
-			return nil
-		case tcell.KeyDown:
-			// Handle arrow keys to navigate dropdown selections (task 5.10)
-			if a.dropdownVisible {
-				a.tviewApp.SetFocus(a.autocompleteDropdown)
-				return nil
-			}
-		case tcell.KeyUp:
-			// Handle arrow keys to navigate dropdown selections (task 5.10)
-			if a.dropdownVisible {
-				a.tviewApp.SetFocus(a.autocompleteDropdown)
-				return nil
-			}
-		}
-		return event
-	})
+// setupKeyBindings dispatches the keys of every component through the keymap.
+// Keys that no action handles reach the component, e.g. for scrolling.
+func (a *App) setupKeyBindings() {
+	a.tviewApp.SetInputCapture(a.captureKeys(contextGlobal))
+	a.inputField.SetInputCapture(a.captureKeys(contextInput))
+	a.split.input.SetInputCapture(a.captureKeys(contextInput))
+	a.autocompleteDropdown.SetInputCapture(a.captureKeys(contextDropdown))
+	a.outputPanel.SetInputCapture(a.captureKeys(contextOutput))
+	a.split.output.SetInputCapture(a.captureKeys(contextOutput))
+	a.violationsList.SetInputCapture(a.captureKeys(contextViolations))
+	a.helpPanel.SetInputCapture(a.captureKeys(contextHelp))
 }
 
-// setupOutputPanelKeyBindings configures key bindings for the output panel
-func (a *App) setupOutputPanelKeyBindings() {
-	a.outputPanel.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Key() {
-		case tcell.KeyEscape:
-			// Return focus to input field
-			a.tviewApp.SetFocus(a.inputField)
-			return nil
-		case tcell.KeyRune:
-			// If user types any character, return focus to input field and pass the character
-			if event.Rune() == 'i' || event.Rune() == 'I' {
-				a.tviewApp.SetFocus(a.inputField)
-				return nil
-			}
-		}
-		// Allow default behavior for arrow keys, PageUp/PageDown for scrolling
-		return event
-	})
+// focusInput moves focus to the input field of the pane used last
+func (a *App) focusInput() {
+	if a.split.visible && a.split.active {
+		a.tviewApp.SetFocus(a.split.input)
+		return
+	}
+	a.tviewApp.SetFocus(a.inputField)
 }
 
-// setupHelpPanelKeyBindings configures key bindings for the help panel
-func (a *App) setupHelpPanelKeyBindings() {
-	a.helpPanel.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Key() {
-		case tcell.KeyEscape:
-			// Hide help panel and return to previous focus
-			a.hideHelpPanel()
-			return nil
-		}
-		// Allow default behavior for arrow keys, PageUp/PageDown, Home/End for scrolling
-		return event
-	})
+// focusOutput moves focus to the panel showing the active document's result
+func (a *App) focusOutput() {
+	if a.split.visible && a.split.active {
+		a.tviewApp.SetFocus(a.split.output)
+		return
+	}
+	if a.compareMode == CompareSideBySide && a.activeDocument < len(a.compareViews) {
+		a.tviewApp.SetFocus(a.compareViews[a.activeDocument])
+		return
+	}
+	a.tviewApp.SetFocus(a.outputPanel)
 }
 
 // Run starts the tview application
 func (a *App) Run() error {
+	// Plugins run off the event loop so a slow one doesn't freeze typing
+	a.pluginsInBackground = true
+	for _, doc := range a.documents {
+		a.runPluginsInBackground(doc.queryEngine, a.documentPluginFinished)
+	}
+	if a.split.engine != nil {
+		a.runPluginsInBackground(a.split.engine, a.splitPluginFinished)
+	}
 	return a.tviewApp.Run()
 }
 
+// runPluginsInBackground makes the plugins of engine run off the event loop
+// once the application runs, calling finished on it when one is done
+func (a *App) runPluginsInBackground(engine *query.Engine, finished func()) {
+	if a.pluginsInBackground {
+		engine.RunPluginsInBackground(func() { a.tviewApp.QueueUpdateDraw(finished) })
+	}
+}
+
 // Stop stops the tview application
 func (a *App) Stop() {
 	a.tviewApp.Stop()
@@ -318,30 +444,110 @@ func (a *App) GetApplication() *tview.Application {
 // setupQueryCallbacks wires up the input field to call the query engine on each keystroke
 func (a *App) setupQueryCallbacks() {
 	a.inputField.SetChangedFunc(func(text string) {
-		// Store the current query
-		a.currentQuery = text
+		if doc := a.currentDocument(); !a.restoring {
+			doc.edits.record(doc.query, text)
+		}
+		a.showQuery(text)
+	})
+}
 
-		// Call the query engine with the current path
-		result := a.queryEngine.Query(text)
+// showQuery runs path from the input field, showing whether it is valid
+func (a *App) showQuery(path string) {
+	result := a.runQuery(path)
+	if result.Pending {
+		a.showMessage(tview.Escape(result.Error), false)
+		return
+	}
 
-		// Update output panel with query results in real-time (task 4.8)
-		a.outputPanel.SetText(result.Value)
+	// Implement visual feedback for invalid paths (task 4.9 & 4.10)
+	a.showValidity(a.inputField, result.IsValid)
+	if result.Failed {
+		a.showMessage(tview.Escape(result.Error), true)
+	}
+}
 
-		// Implement visual feedback for invalid paths (task 4.9 & 4.10)
-		if result.IsValid {
-			// Restore normal color when path becomes valid (task 4.10)
-			a.inputField.SetBorderColor(a.theme.BorderValid)
-		} else {
-			// Change border color to red when path is invalid (task 4.9)
-			a.inputField.SetBorderColor(a.theme.BorderInvalid)
-		}
-	})
+// documentPluginFinished runs the query again if it waited for a plugin of a
+// document engine, now that one finished
+func (a *App) documentPluginFinished() {
+	if a.queryPending {
+		a.showQuery(a.inputField.GetText())
+	}
+}
+
+// splitPluginFinished runs the split pane's query again if it waited for a
+// plugin of the split engine, now that one finished
+func (a *App) splitPluginFinished() {
+	if a.split.visible && a.split.pending {
+		a.querySplit(a.split.input.GetText())
+	}
+}
+
+// runQuery executes path against the active document and displays the result
+func (a *App) runQuery(path string) query.QueryResult {
+	doc := a.currentDocument()
+
+	// The cursor starts at the top of every new result, which starts unfolded
+	if path != doc.query {
+		a.cursor = 0
+		clear(a.collapsed)
+	}
+
+	// Store the current query
+	doc.query = path
+
+	// Call the query engine with the current path
+	result := doc.queryEngine.Query(path)
+	a.queryPending = result.Pending
+
+	// Update output panel with query results in real-time (task 4.8)
+	switch {
+	case a.diffMode:
+		a.renderDiff(path)
+	case a.split.visible:
+		a.split.leftValue = result.Value
+		a.renderSplit()
+	case a.outputView == ViewSchema:
+		a.renderSchema()
+	case a.outputView == ViewCode:
+		a.renderCode()
+	case a.compareMode == CompareOff:
+		a.outputPanel.SetTitle(expandedTitle(doc.queryEngine.GetLastExpanded()))
+		a.renderResult(result.Value)
+	default:
+		a.renderCompare(path)
+	}
+	if !a.cursorActive() {
+		a.statusLine.SetText("")
+	}
+	a.updateWrap()
+	a.updateBreadcrumbs()
+	a.updateInspector()
+	a.refreshStats(result)
+
+	return result
+}
+
+// setOutputView changes what the output panel shows and re-renders it
+func (a *App) setOutputView(view OutputView) {
+	a.outputView = view
+	a.schemaNode = nil
+
+	switch view {
+	case ViewSchema:
+		a.outputPanel.SetTitle(" Schema ")
+	case ViewCode:
+		a.outputPanel.SetTitle(fmt.Sprintf(" %s Types ", a.codeLanguage))
+	default:
+		a.outputPanel.SetTitle("")
+	}
+	a.runQuery(a.inputField.GetText())
 }
 
 // setupFocusHandlers wires up focus change handlers for all focusable components
 func (a *App) setupFocusHandlers() {
 	// Input field focus handler
 	a.inputField.SetFocusFunc(func() {
+		a.split.active = false
 		a.setComponentFocus(FocusInputField)
 	})
 
@@ -352,6 +558,7 @@ func (a *App) setupFocusHandlers() {
 
 	// Output panel focus handler (for scrolling)
 	a.outputPanel.SetFocusFunc(func() {
+		a.split.active = false
 		a.setComponentFocus(FocusOutputPanel)
 	})
 
@@ -359,6 +566,17 @@ func (a *App) setupFocusHandlers() {
 	a.helpPanel.SetFocusFunc(func() {
 		a.setComponentFocus(FocusHelpPanel)
 	})
+
+	// Text views can't change their text while they gain focus, so check before
+	// every draw whether the cursor must be shown or hidden. Split pane
+	// scrolling is synchronized then too so any way of scrolling is mirrored.
+	a.tviewApp.SetBeforeDrawFunc(func(screen tcell.Screen) bool {
+		if shown := a.outputPanel.HasFocus(); shown != a.cursorShown {
+			a.drawCursor(shown)
+		}
+		a.syncSplitScroll()
+		return false
+	})
 }
 
 // showMessage displays a temporary message in the footer (task 6.9)
@@ -382,7 +600,7 @@ func (a *App) showMessage(message string, isError bool) {
 
 // copyToClipboard copies the current output to the clipboard (task 6.7)
 func (a *App) copyToClipboard() {
-	content := a.outputPanel.GetText(false)
+	content := a.outputText()
 	err := export.CopyToClipboard(content)
 	if err != nil {
 		a.showMessage(fmt.Sprintf("Error: %v", err), true)
@@ -391,40 +609,88 @@ func (a *App) copyToClipboard() {
 	}
 }
 
+// outputText returns the text shown in the output panel of the active pane without markup.
+// In schema view the exported form of the schema tree is the JSON Schema.
+func (a *App) outputText() string {
+	switch a.outputView {
+	case ViewSchema:
+		return a.schemaJSON()
+	case ViewCode:
+		return a.outputPanel.GetText(true)
+	}
+	if a.split.visible {
+		// Highlighted differences are markup, so use the plain results
+		if a.split.active {
+			return a.split.rightValue
+		}
+		return a.split.leftValue
+	}
+	if a.diffMode {
+		// The diff view uses color and region tags which must not be exported
+		return a.outputPanel.GetText(true)
+	}
+	if a.compareMode == CompareOff {
+		// The result is shown escaped, with clickable keys and maybe folded
+		return a.currentDocument().queryEngine.GetLastValidValue()
+	}
+	return a.outputPanel.GetText(false)
+}
+
 // showSaveDialog displays a modal dialog to prompt for filename (task 6.6 & 6.8)
 func (a *App) showSaveDialog() {
-	// Create a modal input field for filename
+	switch a.outputView {
+	case ViewSchema:
+		a.showSaveDialogFor(" Export JSON Schema ", "schema.json", a.outputText)
+		return
+	case ViewCode:
+		a.showSaveDialogFor(" Export "+a.codeLanguage.String()+" Types ", "types"+a.codeLanguage.FileExtension(), a.outputText)
+		return
+	}
+	a.showSaveDialogFor(" Save Output ", a.saveFilename, a.outputText)
+}
+
+// showSaveDialogFor prompts for a filename and saves the text returned by content
+func (a *App) showSaveDialogFor(title, defaultFilename string, content func() string) {
+	a.showPrompt(title, "Save to file: ", defaultFilename, func(filename string) {
+		err := export.SaveToFile(content(), filename)
+		if err != nil {
+			a.showMessage(fmt.Sprintf("Error: %v", err), true)
+		} else {
+			a.showMessage(fmt.Sprintf("Saved to %s", filename), false)
+		}
+	})
+}
+
+// showPrompt displays a modal input field and calls onSubmit with the entered
+// text after the main layout has been restored. Empty input is ignored.
+func (a *App) showPrompt(title, label, text string, onSubmit func(text string)) {
+	// Create a modal input field
 	modal := tview.NewInputField().
-		SetLabel("Save to file: ").
+		SetLabel(label).
 		SetFieldWidth(40).
-		SetText("output.json")
+		SetText(text)
 
 	modal.SetBorder(true).
-		SetTitle(" Save Output ").
+		SetTitle(title).
 		SetBorderColor(a.theme.BorderFocused)
 
 	// Create a frame to center the modal
 	frame := tview.NewFrame(modal).
 		SetBorders(2, 2, 2, 2, 4, 4)
 
-	// Handle input
+	// Handle input, returning focus to the component that had it
+	previous := a.tviewApp.GetFocus()
 	modal.SetDoneFunc(func(key tcell.Key) {
 		if key == tcell.KeyEnter {
-			filename := modal.GetText()
-			if filename != "" {
-				content := a.outputPanel.GetText(false)
-				err := export.SaveToFile(content, filename)
-				if err != nil {
-					a.showMessage(fmt.Sprintf("Error: %v", err), true)
-				} else {
-					a.showMessage(fmt.Sprintf("Saved to %s", filename), false)
-				}
-			}
+			value := modal.GetText()
 			// Restore original layout
-			a.restoreLayout()
+			a.restoreLayout(previous)
+			if value != "" {
+				onSubmit(value)
+			}
 		} else if key == tcell.KeyEscape {
 			// Cancel and restore layout
-			a.restoreLayout()
+			a.restoreLayout(previous)
 		}
 	})
 
@@ -433,24 +699,14 @@ func (a *App) showSaveDialog() {
 	a.tviewApp.SetFocus(modal)
 }
 
-// restoreLayout restores the main application layout
-func (a *App) restoreLayout() {
-	if a.dropdownVisible {
-		// Rebuild layout with dropdown
-		a.layout.Clear()
-		a.layout.AddItem(a.inputField, 3, 0, true)
-		a.layout.AddItem(a.autocompleteDropdown, 8, 0, false)
-		a.layout.AddItem(a.outputPanel, 0, 1, false)
-		a.layout.AddItem(a.footer, 1, 0, false)
-	} else {
-		// Rebuild layout without dropdown
-		a.layout.Clear()
-		a.layout.AddItem(a.inputField, 3, 0, true)
-		a.layout.AddItem(a.outputPanel, 0, 1, false)
-		a.layout.AddItem(a.footer, 1, 0, false)
-	}
+// restoreLayout restores the main application layout and focuses a component of it
+func (a *App) restoreLayout(focus tview.Primitive) {
+	a.rebuildLayout()
 	a.tviewApp.SetRoot(a.layout, true)
-	a.tviewApp.SetFocus(a.inputField)
+	if focus == nil {
+		focus = a.inputField
+	}
+	a.tviewApp.SetFocus(focus)
 }
 
 // toggleHelpPanel shows or hides the help panel
@@ -473,22 +729,8 @@ func (a *App) showHelpPanel() {
 
 	a.helpPanelVisible = true
 
-	// Create vertical flex for main content (left side)
-	mainContent := tview.NewFlex().
-		SetDirection(tview.FlexRow)
-
-	mainContent.AddItem(a.inputField, 3, 0, true)
-	if a.dropdownVisible {
-		mainContent.AddItem(a.autocompleteDropdown, 8, 0, false)
-	}
-	mainContent.AddItem(a.outputPanel, 0, 1, false)
-	mainContent.AddItem(a.footer, 1, 0, false)
-
-	// Create horizontal split (50/50) with main content on left, help panel on right
-	a.layout.Clear()
-	a.layout.SetDirection(tview.FlexColumn)
-	a.layout.AddItem(mainContent, 0, 1, true)
-	a.layout.AddItem(a.helpPanel, 0, 1, false)
+	// Rebuild with main content on the left and the help panel on the right
+	a.rebuildLayout()
 
 	// Focus the help panel
 	a.tviewApp.SetFocus(a.helpPanel)
@@ -506,14 +748,7 @@ func (a *App) hideHelpPanel() {
 	a.helpPanel.ScrollToBeginning()
 
 	// Rebuild original vertical layout
-	a.layout.Clear()
-	a.layout.SetDirection(tview.FlexRow)
-	a.layout.AddItem(a.inputField, 3, 0, true)
-	if a.dropdownVisible {
-		a.layout.AddItem(a.autocompleteDropdown, 8, 0, false)
-	}
-	a.layout.AddItem(a.outputPanel, 0, 1, false)
-	a.layout.AddItem(a.footer, 1, 0, false)
+	a.rebuildLayout()
 
 	// Restore focus to component that had it before help opened
 	switch a.focusBeforeHelp {
@@ -522,7 +757,15 @@ func (a *App) hideHelpPanel() {
 	case FocusDropdown:
 		a.tviewApp.SetFocus(a.autocompleteDropdown)
 	case FocusOutputPanel:
-		a.tviewApp.SetFocus(a.outputPanel)
+		a.focusOutput()
+	case FocusSplitInput:
+		a.tviewApp.SetFocus(a.split.input)
+	case FocusSplitOutput:
+		a.tviewApp.SetFocus(a.split.output)
+	case FocusViolations:
+		a.tviewApp.SetFocus(a.violationsList)
+	case FocusPipe:
+		a.tviewApp.SetFocus(a.pipe.view)
 	default:
 		a.tviewApp.SetFocus(a.inputField)
 	}
@@ -547,6 +790,14 @@ func (a *App) setComponentFocus(newFocus FocusableComponent) {
 		a.outputPanel.SetBorderColor(a.theme.BorderUnfocused)
 	case FocusHelpPanel:
 		a.helpPanel.SetBorderColor(a.theme.BorderUnfocused)
+	case FocusSplitInput:
+		a.split.input.SetBorderColor(a.theme.BorderUnfocused)
+	case FocusSplitOutput:
+		a.split.output.SetBorderColor(a.theme.BorderUnfocused)
+	case FocusViolations:
+		a.violationsList.SetBorderColor(a.theme.BorderUnfocused)
+	case FocusPipe:
+		a.pipe.view.SetBorderColor(a.theme.BorderUnfocused)
 	}
 
 	// Apply focus styling to newly focused component
@@ -559,8 +810,17 @@ func (a *App) setComponentFocus(newFocus FocusableComponent) {
 		a.outputPanel.SetBorderColor(a.theme.BorderFocused)
 	case FocusHelpPanel:
 		a.helpPanel.SetBorderColor(a.theme.BorderFocused)
+	case FocusSplitInput:
+		a.split.input.SetBorderColor(a.theme.BorderFocused)
+	case FocusSplitOutput:
+		a.split.output.SetBorderColor(a.theme.BorderFocused)
+	case FocusViolations:
+		a.violationsList.SetBorderColor(a.theme.BorderFocused)
+	case FocusPipe:
+		a.pipe.view.SetBorderColor(a.theme.BorderFocused)
 	}
 
-	// Update tracked focus
+	// Update tracked focus; an unfinished key sequence ends with the focus change
 	a.focusedComponent = newFocus
+	a.pendingKeys = nil
 }
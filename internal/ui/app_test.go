@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gataky/dive/internal/query"
+)
+
+func TestQueryWaitsForPlugin(t *testing.T) {
+	err := query.RegisterPlugins([]query.Plugin{
+		{Name: "testuislow", Command: []string{"sh", "-c", "sleep 0.1; cat"}, Timeout: time.Second},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	finished := make(chan struct{}, 1)
+	app := NewApp([]Document{{Name: "doc.json", JSONData: `{"a": {"b": 1}}`}})
+	app.currentDocument().queryEngine.RunPluginsInBackground(func() { finished <- struct{}{} })
+	app.inputField.SetText("a|@testuislow")
+	if !app.queryPending || !strings.Contains(app.footer.GetText(true), "@testuislow: still running") {
+		t.Errorf("Expected the query to wait for the plugin, got %q", app.footer.GetText(true))
+	}
+	if app.inputField.GetBorderColor() == app.theme.BorderInvalid {
+		t.Error("Expected a waiting path not to be marked invalid")
+	}
+
+	<-finished
+	app.documentPluginFinished()
+	if app.queryPending || app.currentDocument().queryEngine.GetLastValidPath() != "a|@testuislow" {
+		t.Errorf("Expected the query to run again once the plugin finished, got %q", app.currentDocument().queryEngine.GetLastValidPath())
+	}
+	if app.inputField.GetBorderColor() != app.theme.BorderValid {
+		t.Error("Expected the path to be marked valid")
+	}
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gataky/dive/internal/query"
+	"github.com/rivo/tview"
+	"github.com/tidwall/gjson"
+)
+
+// updateBreadcrumbs shows every level of the last valid path of the current
+// document with the type and size of its value
+func (a *App) updateBreadcrumbs() {
+	doc := a.currentDocument()
+
+	// Paths of every level, the deepest first
+	var levels []string
+	for path := doc.queryEngine.GetLastValidPath(); path != ""; path, _ = query.ParentPath(path) {
+		levels = append(levels, path)
+	}
+
+	crumbs := []string{a.breadcrumb("root", gjson.Parse(doc.jsonData), len(levels) == 0)}
+	for i := len(levels) - 1; i >= 0; i-- {
+		_, last := query.ParentPath(levels[i])
+		crumbs = append(crumbs, a.breadcrumb(last, gjson.Get(doc.jsonData, levels[i]), i == 0))
+	}
+	a.breadcrumbs.SetText(" " + strings.Join(crumbs, fmt.Sprintf(" [%s]›[-] ", a.theme.TextMuted)))
+}
+
+// breadcrumb formats one level of the path, highlighting the current one
+func (a *App) breadcrumb(name string, value gjson.Result, current bool) string {
+	color := a.theme.TextDefault
+	if current {
+		color = a.theme.TextAccent
+	}
+	return fmt.Sprintf("[%s]%s[-] [%s]%s[-]", color, tview.Escape(name), a.theme.TextMuted, tview.Escape(shortType(value)))
+}
+
+// shortType describes the type of a value in a few characters: the size of
+// objects and arrays, e.g. {3} and [10], or the name of other types
+func shortType(value gjson.Result) string {
+	switch {
+	case value.IsObject():
+		return fmt.Sprintf("{%d}", len(value.Map()))
+	case value.IsArray():
+		return fmt.Sprintf("[%d]", len(value.Array()))
+	}
+	return valueType(value)
+}
+
+// goToRoot clears the path to show the whole document
+func (a *App) goToRoot() {
+	a.navigate("")
+}
+
+// goToSibling sets the path to the next key or array element of the parent
+// of the current path, or the previous one if direction is -1, wrapping around
+func (a *App) goToSibling(direction int) {
+	doc := a.currentDocument()
+	path := doc.queryEngine.GetLastValidPath()
+	parent, last := query.ParentPath(path)
+
+	value := gjson.Parse(doc.jsonData)
+	if parent != "" {
+		value = gjson.Get(doc.jsonData, parent)
+	}
+	if path == "" || !value.IsObject() && !value.IsArray() {
+		a.showMessage("The current path has no siblings", true)
+		return
+	}
+
+	// Components of every sibling, in document order
+	var siblings []string
+	value.ForEach(func(key, _ gjson.Result) bool {
+		if value.IsObject() {
+			siblings = append(siblings, gjson.Escape(key.String()))
+		} else {
+			siblings = append(siblings, strconv.Itoa(len(siblings)))
+		}
+		return true
+	})
+
+	for i, sibling := range siblings {
+		if sibling == last {
+			next := siblings[((i+direction)%len(siblings)+len(siblings))%len(siblings)]
+			a.navigate(query.JoinPath(parent, next))
+			return
+		}
+	}
+	a.showMessage("The current path has no siblings", true)
+}
@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestBreadcrumbs(t *testing.T) {
+	app := NewApp([]Document{{Name: "doc.json", JSONData: `{"users": [{"name": "Alice", "age": 30}, {"name": "Bob"}], "count": 2}`}})
+
+	app.inputField.SetText("users.0.name")
+	expected := " root {2} › users [2] › 0 {2} › name string"
+	if crumbs := app.breadcrumbs.GetText(true); crumbs != expected {
+		t.Errorf("Expected breadcrumbs %q, got %q", expected, crumbs)
+	}
+
+	tests := []struct {
+		key      tcell.Key
+		expected string
+	}{
+		{tcell.KeyRight, "users.0.age"},
+		{tcell.KeyRight, "users.0.name"},
+		{tcell.KeyUp, "users.0"},
+		{tcell.KeyLeft, "users.1"},
+		{tcell.KeyHome, ""},
+		{tcell.KeyRight, ""},
+	}
+	for _, tt := range tests {
+		app.dispatch(contextGlobal, tcell.NewEventKey(tt.key, 0, tcell.ModAlt))
+		if path := app.inputField.GetText(); path != tt.expected {
+			t.Errorf("Expected Alt+%s to go to %q, got %q", tcell.KeyNames[tt.key], tt.expected, path)
+		}
+	}
+	if crumbs := app.breadcrumbs.GetText(true); crumbs != " root {2}" {
+		t.Errorf("Expected only the root breadcrumb, got %q", crumbs)
+	}
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/gataky/dive/internal/codegen"
+	"github.com/gataky/dive/internal/schema"
+	"github.com/rivo/tview"
+)
+
+// cycleCodeView opens the code preview in the default language, moves it to
+// the next language, and closes it once every language has been shown
+func (a *App) cycleCodeView() {
+	if a.diffMode || a.split.visible || a.compareMode != CompareOff {
+		a.showMessage("Code preview is only available for a single result", true)
+		return
+	}
+
+	if a.outputView != ViewCode {
+		a.setOutputView(ViewCode)
+		return
+	}
+
+	a.codeLanguage = codegen.Languages[(int(a.codeLanguage)+1)%len(codegen.Languages)]
+	if a.codeLanguage == a.defaultLanguage {
+		a.setOutputView(ViewResult)
+	} else {
+		a.setOutputView(ViewCode)
+	}
+}
+
+// renderCode generates type definitions for the current result in the selected language
+func (a *App) renderCode() {
+	// Invalid paths keep showing the types of the last valid result
+	node := schema.Infer(a.currentDocument().queryEngine.GetLastValidRaw())
+	code := codegen.Generate(a.codeLanguage, node, codeRootName(a.inputField.GetText()))
+	a.outputPanel.SetText(tview.Escape(code))
+}
+
+// codeRootName names the generated root type after the last key of the path,
+// so "users" produces a Users type of User elements
+func codeRootName(path string) string {
+	key := path[strings.LastIndex(path, ".")+1:]
+	if key == "" || strings.ContainsAny(key, `\#@*?()|{}[]!=<>%`) ||
+		strings.Trim(key, "-0123456789") == "" {
+		return "Root"
+	}
+	return key
+}
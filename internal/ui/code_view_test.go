@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gataky/dive/internal/codegen"
+)
+
+func TestCodeView(t *testing.T) {
+	app := NewApp([]Document{{Name: "users.json", JSONData: `{"users": [{"id": 1, "tags": ["a"]}, {"id": 2, "name": "B"}]}`}})
+
+	app.inputField.SetText("users")
+	app.cycleCodeView()
+
+	code := app.outputText()
+	if !strings.Contains(code, "type Users []User") || !strings.Contains(code, "Name *string  `json:\"name,omitempty\"`") {
+		t.Errorf("Expected Go types of the users array, got:\n%s", code)
+	}
+
+	app.cycleCodeView()
+	if app.codeLanguage != codegen.TypeScript || !strings.Contains(app.outputText(), "export type Users = User[];") {
+		t.Errorf("Expected TypeScript types, got:\n%s", app.outputText())
+	}
+
+	app.cycleCodeView()
+	if !strings.Contains(app.outputText(), "    tags: Optional[list[str]] = None\n") {
+		t.Errorf("Expected Python dataclasses, got:\n%s", app.outputText())
+	}
+
+	// Cycling past the last language closes the preview
+	app.cycleCodeView()
+	if app.outputView != ViewResult || !strings.Contains(app.outputText(), `"id": 1`) {
+		t.Errorf("Expected plain result after closing code preview, got:\n%s", app.outputText())
+	}
+}
+
+func TestCodeRootName(t *testing.T) {
+	tests := map[string]string{
+		"":                "Root",
+		"users":           "users",
+		"data.items":      "items",
+		"users.0":         "Root",
+		"users.#.address": "address",
+		"users.#(age>1)#": "Root",
+	}
+
+	for path, expected := range tests {
+		if got := codeRootName(path); got != expected {
+			t.Errorf("Expected root name %q for %q, got %q", expected, path, got)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"fmt"
+
 	"github.com/gataky/dive/internal/ui/theme"
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -8,44 +10,43 @@ import (
 
 // createInputField creates the path input field component
 func createInputField(th *theme.Theme) *tview.InputField {
-	style := (tcell.Style{}).
-	Background(th.Background)
-
 	inputField := tview.NewInputField().
 		SetFieldWidth(0). // Use all available width
-		SetPlaceholder("Enter gjson path (e.g., users.0.name)").
-		SetFieldBackgroundColor(th.FieldBackground).
-		SetPlaceholderStyle(style)
+		SetPlaceholder("Enter gjson path (e.g., users.0.name)")
 
-	inputField.SetBorder(true).
-		SetBorderColor(th.BorderUnfocused).
-		SetBackgroundColor(th.Background)
+	inputField.SetBorder(true)
+	styleInputField(inputField, th)
 
 	return inputField
 }
 
+// styleInputField applies the theme colors to an input field
+func styleInputField(inputField *tview.InputField, th *theme.Theme) {
+	inputField.
+		SetFieldBackgroundColor(th.FieldBackground).
+		SetFieldTextColor(th.TextDefault).
+		SetLabelColor(th.TextAccent).
+		SetPlaceholderStyle(tcell.StyleDefault.Foreground(th.TextPlaceholder).Background(th.FieldBackground))
+	styleBox(inputField.Box, th)
+}
+
 // createOutputPanel creates the output panel component for displaying query results
 func createOutputPanel(th *theme.Theme) *tview.TextView {
-	style := (tcell.Style{}).
-	Background(th.Background)
-
 	outputPanel := tview.NewTextView().
 		SetDynamicColors(true).
+		SetRegions(true).
 		SetScrollable(true).
 		SetWordWrap(true).
-		SetTextColor(th.TextDefault).
 		SetChangedFunc(func() {
 			// Auto-scroll to the end when content changes
 			// This can be disabled if we want to maintain scroll position
-		}).
-		SetTextStyle(style)
+		})
 
-	outputPanel.SetBorder(true).
-		SetBorderColor(th.BorderUnfocused).
-		SetBackgroundColor(th.Background)
+	outputPanel.SetBorder(true)
+	styleTextView(outputPanel, th)
 
 	// Set initial message
-	outputPanel.SetText("[gray]Enter a gjson path to query the JSON data...[-]")
+	outputPanel.SetText(fmt.Sprintf("[%s]Enter a gjson path to query the JSON data...[-]", th.TextMuted))
 
 	return outputPanel
 }
@@ -54,46 +55,106 @@ func createOutputPanel(th *theme.Theme) *tview.TextView {
 func createFooter(th *theme.Theme) *tview.TextView {
 	footer := tview.NewTextView().
 		SetDynamicColors(true).
-		SetTextAlign(tview.AlignCenter).
-		SetTextColor(th.TextDefault).
-		SetText("[white::b]Tab[::-]: Autocomplete | [white::b]Ctrl+O[::-]: Focus Output | [white::b]Ctrl+C[::-]: Copy | [white::b]Ctrl+S[::-]: Save | [white::b]Ctrl+Q[::-]: Quit")
+		SetRegions(true).
+		SetTextAlign(tview.AlignCenter)
 
-	footer.SetBackgroundColor(th.Background)
+	styleTextView(footer, th)
 
 	return footer
 }
 
+// createBar creates a one-line bar such as the status line or the breadcrumbs
+func createBar(th *theme.Theme) *tview.TextView {
+	bar := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+
+	styleTextView(bar, th)
+
+	return bar
+}
+
+// createTabBar creates the one-line bar listing the open documents
+func createTabBar(th *theme.Theme) *tview.TextView {
+	tabBar := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+
+	styleTextView(tabBar, th)
+
+	return tabBar
+}
+
+// styleTextView applies the theme colors to a text view
+func styleTextView(view *tview.TextView, th *theme.Theme) {
+	view.SetTextStyle(tcell.StyleDefault.Foreground(th.TextDefault).Background(th.Background))
+	styleBox(view.Box, th)
+}
+
 // createAutocompleteDropdown creates the autocomplete dropdown using tview.List
 func createAutocompleteDropdown(th *theme.Theme) *tview.List {
 	dropdown := tview.NewList().
 		ShowSecondaryText(false).
-		SetHighlightFullLine(true).
-		SetMainTextColor(th.TextDefault)
+		SetHighlightFullLine(true)
 
-	dropdown.SetBorder(true).
-		SetBorderColor(th.BorderUnfocused).
-		SetBackgroundColor(th.Background)
+	dropdown.SetBorder(true)
+	styleList(dropdown, th)
 
 	return dropdown
 }
 
-// createHelpPanel creates the help panel component for displaying gjson syntax help
-func createHelpPanel(th *theme.Theme) *tview.TextView {
-	style := (tcell.Style{}).
-		Background(th.Background)
+// createViolationsList creates the list of JSON Schema violations shown below the output panel
+func createViolationsList(th *theme.Theme) *tview.List {
+	list := tview.NewList().
+		ShowSecondaryText(false).
+		SetHighlightFullLine(true)
+
+	list.SetBorder(true).
+		SetTitle(" Violations ")
+	styleList(list, th)
+
+	return list
+}
+
+// styleList applies the theme colors to a list. The selected item is shown
+// in reverse video so it stands out in every theme.
+func styleList(list *tview.List, th *theme.Theme) {
+	list.SetMainTextStyle(tcell.StyleDefault.Foreground(th.TextDefault).Background(th.Background)).
+		SetSelectedStyle(tcell.StyleDefault.Reverse(true))
+	styleBox(list.Box, th)
+}
+
+// createStatsPanel creates the panel showing statistics of the current result
+func createStatsPanel(th *theme.Theme) *tview.TextView {
+	panel := tview.NewTextView().
+		SetDynamicColors(false).
+		SetWrap(false)
+
+	panel.SetBorder(true).
+		SetTitle(" Statistics ")
+	styleTextView(panel, th)
+
+	return panel
+}
 
+// createHelpPanel creates the help panel component for displaying the key bindings and gjson syntax help
+func createHelpPanel(th *theme.Theme, content string) *tview.TextView {
 	helpPanel := tview.NewTextView().
 		SetDynamicColors(true).
 		SetScrollable(true).
 		SetWordWrap(true).
-		SetTextColor(th.TextDefault).
-		SetText(getHelpContent()).
-		SetTextStyle(style)
+		SetText(content)
 
 	helpPanel.SetBorder(true).
-		SetTitle(" gjson Syntax Help ").
-		SetBorderColor(th.BorderFocused).
-		SetBackgroundColor(th.Background)
+		SetTitle(" Help ")
+	styleTextView(helpPanel, th)
 
 	return helpPanel
 }
+
+// styleBox applies the theme's border, title and background colors to a component
+func styleBox(box *tview.Box, th *theme.Theme) {
+	box.SetBorderColor(th.BorderUnfocused).
+		SetTitleColor(th.TextDefault).
+		SetBackgroundColor(th.Background)
+}
@@ -8,7 +8,7 @@ import (
 
 func TestCreateHelpPanel(t *testing.T) {
 	th := theme.DefaultTheme()
-	helpPanel := createHelpPanel(th)
+	helpPanel := createHelpPanel(th, getHelpContent(th))
 
 	// Test that the function returns a non-nil TextView
 	if helpPanel == nil {
@@ -18,7 +18,7 @@ func TestCreateHelpPanel(t *testing.T) {
 
 func TestCreateHelpPanelTitle(t *testing.T) {
 	th := theme.DefaultTheme()
-	helpPanel := createHelpPanel(th)
+	helpPanel := createHelpPanel(th, getHelpContent(th))
 
 	// Test that the panel has the correct title
 	// Note: tview doesn't provide a direct getter for title, but we can verify
@@ -41,7 +41,7 @@ func TestCreateHelpPanelTitle(t *testing.T) {
 
 func TestCreateHelpPanelScrollable(t *testing.T) {
 	th := theme.DefaultTheme()
-	helpPanel := createHelpPanel(th)
+	helpPanel := createHelpPanel(th, getHelpContent(th))
 
 	if helpPanel == nil {
 		t.Fatal("createHelpPanel() returned nil")
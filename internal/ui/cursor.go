@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gataky/dive/internal/query"
+	"github.com/rivo/tview"
+	"github.com/tidwall/gjson"
+)
+
+// cursorActive reports whether the output panel shows a single result whose
+// lines have a cursor
+func (a *App) cursorActive() bool {
+	return a.outputView == ViewResult && !a.diffMode && !a.split.visible &&
+		a.compareMode == CompareOff && len(a.outputLines) > 0
+}
+
+// drawCursor shows the lines of the result with the cursor line highlighted
+// when shown is true, and the path under the cursor in the status line
+func (a *App) drawCursor(shown bool) {
+	a.cursorShown = shown
+	a.updateWrap()
+	if !a.cursorActive() {
+		a.statusLine.SetText("")
+		return
+	}
+
+	var b strings.Builder
+	for i, line := range a.outputLines {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		if shown && i == a.cursor {
+			b.WriteString("[::r]" + line + "[::-]")
+		} else {
+			b.WriteString(line)
+		}
+	}
+	a.outputPanel.SetText(b.String())
+	a.updateStatusLine()
+	a.updateInspector()
+}
+
+// updateWrap wraps the lines of the output panel as set, except while the
+// cursor is shown: it moves and scrolls by lines of the result, which must
+// each take up one row
+func (a *App) updateWrap() {
+	a.outputPanel.SetWrap(a.wrap && !(a.cursorShown && a.cursorActive()))
+}
+
+// moveCursor moves the cursor to a line of the result and scrolls the output
+// panel so the line is visible
+func (a *App) moveCursor(line int) {
+	a.cursor = min(max(line, 0), len(a.outputLines)-1)
+
+	row, col := scrollOffset(a.outputPanel)
+	_, _, _, height := a.outputPanel.GetInnerRect()
+	switch {
+	case a.cursor < row:
+		a.outputPanel.ScrollTo(a.cursor, col)
+	case height > 0 && a.cursor >= row+height:
+		a.outputPanel.ScrollTo(a.cursor-height+1, col)
+	}
+	a.drawCursor(a.outputPanel.HasFocus())
+}
+
+// cursorPath returns the full path of the value under the cursor
+func (a *App) cursorPath() string {
+	return query.JoinPath(a.currentDocument().queryEngine.GetLastValidPath(), a.linePaths[a.cursor])
+}
+
+// updateStatusLine shows the path and type of the value under the cursor
+func (a *App) updateStatusLine() {
+	path := a.cursorPath()
+	if path == "" {
+		path = "@this"
+	}
+
+	value := gjson.Parse(a.currentDocument().queryEngine.GetLastValidRaw())
+	if relative := a.linePaths[a.cursor]; relative != "" {
+		value = value.Get(relative)
+	}
+	a.statusLine.SetText(fmt.Sprintf(" [%s::b]%s[-::-]  [%s]%s[-]",
+		a.theme.TextKey, tview.Escape(path), a.theme.TextMuted, valueType(value)))
+}
+
+// valueType describes the JSON type of a value, with the size of objects and arrays
+func valueType(value gjson.Result) string {
+	switch {
+	case value.IsObject():
+		return fmt.Sprintf("object, %d keys", len(value.Map()))
+	case value.IsArray():
+		return fmt.Sprintf("array, %d items", len(value.Array()))
+	}
+	switch value.Type {
+	case gjson.String:
+		return "string"
+	case gjson.Number:
+		return "number"
+	case gjson.True, gjson.False:
+		return "boolean"
+	case gjson.Null:
+		return "null"
+	}
+	return "invalid JSON"
+}
+
+// drillDown sets the path to the value under the cursor
+func (a *App) drillDown() {
+	if !a.cursorActive() || a.linePaths[a.cursor] == "" {
+		return
+	}
+	a.navigate(a.cursorPath())
+}
+
+// drillUp sets the path to the parent of the current path and moves the
+// cursor to the value the path pointed to
+func (a *App) drillUp() {
+	path := a.currentDocument().queryEngine.GetLastValidPath()
+	if path == "" {
+		a.showMessage("Already at the top of the document", true)
+		return
+	}
+
+	parent, last := query.ParentPath(path)
+	a.navigate(parent)
+	a.moveCursorToPath(last)
+}
+
+// moveCursorToPath moves the cursor to the first line of the value at path,
+// relative to the result
+func (a *App) moveCursorToPath(path string) {
+	for i, linePath := range a.linePaths {
+		if linePath == path {
+			a.moveCursor(i)
+			return
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestCursorDrillDown(t *testing.T) {
+	app := NewApp([]Document{{Name: "doc.json", JSONData: `{"users": [{"name": "Alice"}, {"name": "Bob"}]}`}})
+	app.tviewApp.SetFocus(app.outputPanel)
+	press := func(key tcell.Key) {
+		app.dispatch(contextOutput, tcell.NewEventKey(key, 0, tcell.ModNone))
+	}
+
+	press(tcell.KeyDown)
+	if status := app.statusLine.GetText(true); !strings.Contains(status, "users  array, 2 items") {
+		t.Errorf("Expected the status line to show the users array, got %q", status)
+	}
+	if !strings.Contains(app.outputPanel.GetText(false), `[::r]  ["L1"]"users"[""]: [[::-]`) {
+		t.Errorf("Expected the cursor line to be highlighted, got:\n%s", app.outputPanel.GetText(false))
+	}
+
+	press(tcell.KeyEnter)
+	if app.inputField.GetText() != "users" || app.cursor != 0 {
+		t.Errorf("Expected Enter to drill into users, got %q at line %d", app.inputField.GetText(), app.cursor)
+	}
+
+	// Line 5 is the name of the second user
+	for range 5 {
+		press(tcell.KeyDown)
+	}
+	press(tcell.KeyEnter)
+	if app.inputField.GetText() != "users.1.name" {
+		t.Errorf("Expected Enter to drill into users.1.name, got %q", app.inputField.GetText())
+	}
+	if status := app.statusLine.GetText(true); !strings.Contains(status, "users.1.name  string") {
+		t.Errorf("Expected the status line to show a string, got %q", status)
+	}
+
+	// Backspace goes back up with the cursor on the value it came from
+	tests := []struct {
+		path string
+		line int
+	}{
+		{"users.1", 1},
+		{"users", 4},
+		{"", 1},
+	}
+	for _, tt := range tests {
+		press(tcell.KeyBackspace2)
+		if app.inputField.GetText() != tt.path || app.cursor != tt.line {
+			t.Errorf("Expected Backspace to go up to %q at line %d, got %q at line %d", tt.path, tt.line, app.inputField.GetText(), app.cursor)
+		}
+	}
+}
+
+func TestCursorTurnsOffWrapping(t *testing.T) {
+	app := NewApp([]Document{{Name: "doc.json", JSONData: `{"a": "` + strings.Repeat("long ", 20) + `", "b": 1}`}})
+	screen := tcell.NewSimulationScreen("")
+	screen.Init()
+	defer screen.Fini()
+	app.outputPanel.SetRect(0, 0, 30, 10)
+	app.outputPanel.Draw(screen)
+
+	// Lines wrap while the input field is focused
+	if rows := app.outputPanel.GetWrappedLineCount(); rows <= len(app.outputLines) {
+		t.Errorf("Expected the long line to wrap, got %d rows for %d lines", rows, len(app.outputLines))
+	}
+
+	// Each line is one row while the cursor moves over them
+	app.tviewApp.SetFocus(app.outputPanel)
+	app.drawCursor(true)
+	app.outputPanel.Draw(screen)
+	if rows := app.outputPanel.GetWrappedLineCount(); rows != len(app.outputLines) {
+		t.Errorf("Expected a row for every line with the cursor shown, got %d rows for %d lines", rows, len(app.outputLines))
+	}
+	app.dispatch(contextOutput, tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone))
+	app.dispatch(contextOutput, tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone))
+	if path := app.cursorPath(); path != "b" {
+		t.Errorf("Expected the cursor on b after two lines, got %q", path)
+	}
+}
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gataky/dive/internal/config"
+	"github.com/gataky/dive/internal/diff"
+	"github.com/rivo/tview"
+	"github.com/tidwall/gjson"
+)
+
+// maxDiffValueLength is the number of characters of a value shown per change line
+const maxDiffValueLength = 80
+
+// NewDiffApp creates an application showing the structural differences between
+// two documents using the default configuration. The input field scopes the
+// diff to a gjson path.
+func NewDiffApp(oldDoc, newDoc Document, opts diff.Options) *App {
+	// The default configuration is always valid
+	app, _ := NewDiffAppWithConfig(oldDoc, newDoc, opts, config.Default())
+	return app
+}
+
+// NewDiffAppWithConfig creates the diff application with the theme, key
+// bindings and defaults of cfg
+func NewDiffAppWithConfig(oldDoc, newDoc Document, opts diff.Options, cfg *config.Config) (*App, error) {
+	app, err := NewAppWithConfig([]Document{oldDoc, newDoc}, cfg)
+	if err != nil {
+		return nil, err
+	}
+	app.diffMode = true
+	app.diffOptions = opts
+	app.originalFooterText = app.footerText()
+	app.footer.SetText(app.originalFooterText)
+	app.outputPanel.SetRegions(true)
+	app.outputPanel.SetTitle(fmt.Sprintf(" %s → %s ", tview.Escape(oldDoc.Name), tview.Escape(newDoc.Name)))
+
+	app.runQuery("")
+
+	return app, nil
+}
+
+// renderDiff compares the values at scope in the first two documents and lists the changes
+func (a *App) renderDiff(scope string) {
+	oldJSON, newJSON := a.documents[0].jsonData, a.documents[1].jsonData
+	var changes []diff.Change
+	var err error
+	if scope != "" {
+		oldValue, newValue := gjson.Get(oldJSON, scope), gjson.Get(newJSON, scope)
+		switch {
+		case !oldValue.Exists() && !newValue.Exists():
+			// Keep the last diff while the path is being typed
+			return
+		case !oldValue.Exists():
+			// The scoped value is added or removed as a whole, which replace can't patch
+			changes = []diff.Change{{Type: diff.Added, NewRaw: newValue.Raw}}
+		case !newValue.Exists():
+			changes = []diff.Change{{Type: diff.Removed, OldRaw: oldValue.Raw}}
+		}
+		oldJSON, newJSON = oldValue.Raw, newValue.Raw
+	}
+
+	if changes == nil {
+		changes, err = diff.Compare(oldJSON, newJSON, a.diffOptions)
+	}
+	if err != nil {
+		a.outputPanel.SetText(fmt.Sprintf("[%s]Error: %s[-]", a.theme.ColorError, tview.Escape(err.Error())))
+		a.diffChanges = nil
+		return
+	}
+
+	// A scope that is not a plain path keeps patch pointers relative to the scoped value
+	changes, scopeErr := diff.Scope(changes, scope)
+	a.diffChanges = changes
+	a.diffSelected = 0
+
+	var b strings.Builder
+	if len(changes) == 0 {
+		fmt.Fprintf(&b, "[%s]No differences[-]", a.theme.ColorSuccess)
+	} else {
+		fmt.Fprintf(&b, "%d change(s)\n", len(changes))
+		if scopeErr != nil {
+			fmt.Fprintf(&b, "[%s]Exported patch paths are relative to the scoped value[-]\n", a.theme.TextMuted)
+		}
+		b.WriteString("\n")
+	}
+	for i, c := range changes {
+		fmt.Fprintf(&b, "[\"%d\"]%s[\"\"]\n", i, a.formatChange(c))
+	}
+
+	a.outputPanel.SetText(b.String())
+	a.outputPanel.ScrollToBeginning()
+	a.selectChange(0)
+}
+
+// formatChange renders a single change as a colored line
+func (a *App) formatChange(c diff.Change) string {
+	path := c.Path
+	if path == "" {
+		path = "(root)"
+	}
+
+	switch c.Type {
+	case diff.Added:
+		return fmt.Sprintf("[%s]+ %s[-]: %s", a.theme.ColorSuccess, tview.Escape(path), tview.Escape(compactValue(c.NewRaw)))
+	case diff.Removed:
+		return fmt.Sprintf("[%s]- %s[-]: %s", a.theme.ColorError, tview.Escape(path), tview.Escape(compactValue(c.OldRaw)))
+	default:
+		return fmt.Sprintf("[%s]~ %s[-]: %s → %s", a.theme.TextAccent, tview.Escape(path),
+			tview.Escape(compactValue(c.OldRaw)), tview.Escape(compactValue(c.NewRaw)))
+	}
+}
+
+// selectChange highlights the change at index and scrolls it into view
+func (a *App) selectChange(index int) {
+	if len(a.diffChanges) == 0 {
+		return
+	}
+
+	// Wrap around at both ends
+	index = (index + len(a.diffChanges)) % len(a.diffChanges)
+	a.diffSelected = index
+	a.outputPanel.Highlight(strconv.Itoa(index))
+	a.outputPanel.ScrollToHighlight()
+}
+
+// diffPatch returns the current diff as an RFC 6902 JSON Patch
+func (a *App) diffPatch() string {
+	return diff.Patch(a.diffChanges)
+}
+
+// compactValue returns raw JSON on a single line, truncated for display
+func compactValue(raw string) string {
+	value := gjson.Parse(raw).Get("@ugly").Raw
+	if value == "" {
+		value = raw
+	}
+	if runes := []rune(value); len(runes) > maxDiffValueLength {
+		value = string(runes[:maxDiffValueLength-3]) + "..."
+	}
+	return value
+}
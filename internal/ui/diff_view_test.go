@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gataky/dive/internal/diff"
+)
+
+func TestDiffAppListsChanges(t *testing.T) {
+	app := NewDiffApp(
+		Document{Name: "old.json", JSONData: `{"envs": {"prod": {"port": 80, "debug": false}}, "name": "svc"}`},
+		Document{Name: "new.json", JSONData: `{"name": "svc", "envs": {"prod": {"port": 8080}}}`},
+		diff.Options{},
+	)
+
+	if len(app.diffChanges) != 2 {
+		t.Fatalf("Expected 2 changes, got %+v", app.diffChanges)
+	}
+
+	text := app.outputText()
+	if !strings.Contains(text, "~ envs.prod.port: 80 → 8080") {
+		t.Errorf("Expected changed port in diff view, got:\n%s", text)
+	}
+	if !strings.Contains(text, "- envs.prod.debug: false") {
+		t.Errorf("Expected removed debug flag in diff view, got:\n%s", text)
+	}
+}
+
+func TestDiffAppScopeAndNavigation(t *testing.T) {
+	app := NewDiffApp(
+		Document{Name: "old.json", JSONData: `{"a": {"x": 1}, "b": {"y": 1, "z": 1}}`},
+		Document{Name: "new.json", JSONData: `{"a": {"x": 2}, "b": {"y": 2, "z": 2}}`},
+		diff.Options{},
+	)
+
+	app.inputField.SetText("b")
+	if len(app.diffChanges) != 2 {
+		t.Fatalf("Expected 2 changes in scope 'b', got %+v", app.diffChanges)
+	}
+	if app.diffChanges[0].Path != "b.y" || app.diffChanges[0].Pointer != "/b/y" {
+		t.Errorf("Expected scoped path and pointer, got %+v", app.diffChanges[0])
+	}
+
+	app.selectChange(app.diffSelected + 1)
+	if app.diffSelected != 1 {
+		t.Errorf("Expected second change to be selected, got %d", app.diffSelected)
+	}
+	app.selectChange(app.diffSelected + 1)
+	if app.diffSelected != 0 {
+		t.Errorf("Expected selection to wrap around, got %d", app.diffSelected)
+	}
+
+	if patch := app.diffPatch(); !strings.Contains(patch, `"path": "/b/z"`) {
+		t.Errorf("Expected patch to contain scoped pointer, got:\n%s", patch)
+	}
+}
+
+// applyPatch applies an RFC 6902 patch of add, remove and replace operations
+// on object members, failing like the RFC requires when a target is missing
+func applyPatch(t *testing.T, doc, patch string) string {
+	t.Helper()
+	var value any
+	var ops []struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}
+	if err := json.Unmarshal([]byte(doc), &value); err != nil {
+		t.Fatalf("Invalid document: %v", err)
+	}
+	if err := json.Unmarshal([]byte(patch), &ops); err != nil {
+		t.Fatalf("Invalid patch: %v", err)
+	}
+
+	for _, op := range ops {
+		tokens := strings.Split(op.Path, "/")[1:]
+		parent := value.(map[string]any)
+		for _, token := range tokens[:len(tokens)-1] {
+			parent = parent[token].(map[string]any)
+		}
+		key := tokens[len(tokens)-1]
+		if _, exists := parent[key]; !exists && op.Op != "add" {
+			t.Fatalf("Can't %s %s, which doesn't exist", op.Op, op.Path)
+		}
+		if op.Op == "remove" {
+			delete(parent, key)
+		} else {
+			parent[key] = op.Value
+		}
+	}
+
+	result, _ := json.Marshal(value)
+	return string(result)
+}
+
+func TestDiffPatchOfMissingScope(t *testing.T) {
+	oldJSON, newJSON := `{"a":{"b":1}}`, `{"c":1}`
+	app := NewDiffApp(Document{Name: "old.json", JSONData: oldJSON}, Document{Name: "new.json", JSONData: newJSON}, diff.Options{})
+
+	// a only exists in the old document, so the patch removes it
+	app.inputField.SetText("a")
+	if patched := applyPatch(t, oldJSON, app.diffPatch()); patched != `{}` {
+		t.Errorf("Expected a to be removed, got %s", patched)
+	}
+
+	// c only exists in the new document, so the patch adds it
+	app.inputField.SetText("c")
+	if patched := applyPatch(t, oldJSON, app.diffPatch()); patched != `{"a":{"b":1},"c":1}` {
+		t.Errorf("Expected c to be added, got %s", patched)
+	}
+}
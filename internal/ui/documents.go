@@ -0,0 +1,283 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gataky/dive/internal/input"
+	"github.com/gataky/dive/internal/query"
+	"github.com/rivo/tview"
+)
+
+// Document is a named JSON document opened in the viewer
+type Document struct {
+	Name     string // Display name shown in the tab bar, usually the file path
+	Path     string // File the document was read from, empty for stdin
+	JSONData string // Raw JSON content
+
+	Compression *input.Compression // How the file was compressed, nil if it wasn't
+	Relaxed     []string           // JSONC/JSON5 syntax the file used, see input.Relax
+}
+
+// CompareMode controls how the current path is evaluated across open documents
+type CompareMode int
+
+const (
+	CompareOff        CompareMode = iota // Only the active document is queried
+	CompareSideBySide                    // Results of every document are shown next to each other
+	CompareCombined                      // Results are merged into a single {file: result} object
+)
+
+// String returns a human readable name for the compare mode
+func (m CompareMode) String() string {
+	switch m {
+	case CompareSideBySide:
+		return "side by side"
+	case CompareCombined:
+		return "combined"
+	default:
+		return "off"
+	}
+}
+
+// documentState holds the query engine and view state of a single open document
+type documentState struct {
+	name        string
+	path        string // File the document is saved to
+	jsonData    string
+	saved       string // Content of the file as last read or saved
+	compression *input.Compression
+	relaxed     []string
+	queryEngine *query.Engine
+	query       string   // Current text of the input field
+	history     []string // Previously submitted queries, oldest first
+	historyPos  int      // Position while browsing history, len(history) when not browsing
+	scrollRow   int      // Output panel scroll position
+	scrollCol   int
+	visits      pathHistory // Paths visited, for going back and forward
+	edits       editHistory // Texts of the input field, for undo and redo
+	changes     documentChanges
+}
+
+// documentChanges holds the contents of a document before and after the
+// current one for undoing and redoing changes made in edit mode
+type documentChanges struct {
+	undo []string
+	redo []string
+}
+
+// newDocumentState creates the state for a newly opened document
+func newDocumentState(doc Document) *documentState {
+	return &documentState{
+		name:        doc.Name,
+		path:        doc.Path,
+		jsonData:    doc.JSONData,
+		saved:       doc.JSONData,
+		compression: doc.Compression,
+		relaxed:     doc.Relaxed,
+		queryEngine: query.NewEngine(doc.JSONData),
+	}
+}
+
+// modified reports whether the document was changed since it was read or saved
+func (d *documentState) modified() bool {
+	return d.jsonData != d.saved
+}
+
+// recordHistory appends a query to the history, skipping empty and repeated entries
+func (d *documentState) recordHistory(q string) {
+	if q != "" && (len(d.history) == 0 || d.history[len(d.history)-1] != q) {
+		d.history = append(d.history, q)
+	}
+	d.historyPos = len(d.history)
+}
+
+// previousHistory returns the previous query in the history, if any
+func (d *documentState) previousHistory() (string, bool) {
+	if d.historyPos == 0 {
+		return "", false
+	}
+	d.historyPos--
+	return d.history[d.historyPos], true
+}
+
+// nextHistory returns the next query in the history. Moving past the newest
+// entry returns an empty query, like a shell prompt.
+func (d *documentState) nextHistory() (string, bool) {
+	if d.historyPos >= len(d.history) {
+		return "", false
+	}
+	d.historyPos++
+	if d.historyPos == len(d.history) {
+		return "", true
+	}
+	return d.history[d.historyPos], true
+}
+
+// currentDocument returns the state of the active document
+func (a *App) currentDocument() *documentState {
+	return a.documents[a.activeDocument]
+}
+
+// documentNames returns the names of all open documents in tab order
+func (a *App) documentNames() []string {
+	names := make([]string, len(a.documents))
+	for i, doc := range a.documents {
+		names[i] = doc.name
+	}
+	return names
+}
+
+// documentEngines returns the query engines of all open documents in tab order
+func (a *App) documentEngines() []*query.Engine {
+	engines := make([]*query.Engine, len(a.documents))
+	for i, doc := range a.documents {
+		engines[i] = doc.queryEngine
+	}
+	return engines
+}
+
+// switchDocument makes the document at index active, saving the view state of
+// the current document and restoring the one of the new document
+func (a *App) switchDocument(index int) {
+	if index < 0 || index >= len(a.documents) || index == a.activeDocument {
+		return
+	}
+
+	current := a.currentDocument()
+	current.query = a.inputField.GetText()
+	current.scrollRow, current.scrollCol = a.outputPanel.GetScrollOffset()
+
+	a.activeDocument = index
+	next := a.currentDocument()
+
+	a.hideDropdown()
+	// Folds belong to the result they were made in
+	clear(a.collapsed)
+	// SetText only fires the changed func when the text differs, so run the query explicitly
+	a.restoreText(next.query)
+	a.runQuery(next.query)
+	a.outputPanel.ScrollTo(next.scrollRow, next.scrollCol)
+	if a.split.visible {
+		// The split pane always shows the active document
+		a.split.engine = a.newSplitEngine(next.jsonData)
+		a.split.rightValue = a.split.engine.Query(a.split.input.GetText()).Value
+		a.renderSplit()
+	}
+	if a.violationsVisible {
+		a.validateDocument()
+	}
+	a.updateTabBar()
+	// The footer shows the compression and relaxed syntax of the active document
+	a.originalFooterText = a.footerText()
+	a.footer.SetText(a.originalFooterText)
+}
+
+// addDocument adds a document after the open ones
+func (a *App) addDocument(doc Document) {
+	state := newDocumentState(doc)
+	state.queryEngine.SetExpandStrings(a.expandStrings)
+	a.runPluginsInBackground(state.queryEngine, a.documentPluginFinished)
+	a.documents = append(a.documents, state)
+}
+
+// openDocument opens a document in a new tab and switches to it
+func (a *App) openDocument(doc Document) {
+	a.addDocument(doc)
+	a.rebuildLayout()
+	a.switchDocument(len(a.documents) - 1)
+}
+
+// nextDocument switches to the next document, wrapping around at the end
+func (a *App) nextDocument() {
+	if len(a.documents) < 2 {
+		return
+	}
+	a.switchDocument((a.activeDocument + 1) % len(a.documents))
+}
+
+// previousDocument switches to the previous document, wrapping around at the start
+func (a *App) previousDocument() {
+	if len(a.documents) < 2 {
+		return
+	}
+	a.switchDocument((a.activeDocument - 1 + len(a.documents)) % len(a.documents))
+}
+
+// updateTabBar renders the document tabs, highlighting the active document
+func (a *App) updateTabBar() {
+	var b strings.Builder
+	for i, doc := range a.documents {
+		name := tview.Escape(doc.name)
+		if doc.modified() {
+			name += "*"
+		}
+		if i == a.activeDocument {
+			fmt.Fprintf(&b, "[%s::b] %d:%s [-::-]", a.theme.TextAccent, i+1, name)
+		} else {
+			fmt.Fprintf(&b, "[%s] %d:%s [-]", a.theme.TextMuted, i+1, name)
+		}
+	}
+	if a.compareMode != CompareOff {
+		fmt.Fprintf(&b, " [%s]compare: %s[-]", a.theme.TextMuted, a.compareMode)
+	}
+	if a.editMode {
+		fmt.Fprintf(&b, " [%s]edit mode[-]", a.theme.TextMuted)
+	}
+	a.tabBar.SetText(b.String())
+}
+
+// cycleCompareMode switches between single, side-by-side and combined evaluation
+func (a *App) cycleCompareMode() {
+	if len(a.documents) < 2 {
+		a.showMessage("Compare mode needs more than one document", true)
+		return
+	}
+	if a.outputView != ViewResult {
+		a.showMessage("Close the "+a.outputView.String()+" first", true)
+		return
+	}
+
+	a.compareMode = (a.compareMode + 1) % 3
+
+	if a.compareMode == CompareSideBySide {
+		a.compareViews = make([]*tview.TextView, len(a.documents))
+		for i, doc := range a.documents {
+			view := a.newOutputPanel()
+			view.SetTitle(" " + tview.Escape(doc.name) + " ")
+			view.SetInputCapture(a.captureKeys(contextOutput))
+			view.SetFocusFunc(func() {
+				a.setComponentFocus(FocusOutputPanel)
+			})
+			a.compareViews[i] = view
+		}
+	} else {
+		a.compareViews = nil
+	}
+
+	a.rebuildLayout()
+	a.runQuery(a.inputField.GetText())
+	a.updateTabBar()
+	a.showMessage(fmt.Sprintf("Compare mode: %s", a.compareMode), false)
+}
+
+// renderCompare shows the result of path for every open document
+func (a *App) renderCompare(path string) {
+	results := query.QueryAll(a.documentEngines(), path)
+
+	switch a.compareMode {
+	case CompareSideBySide:
+		for i, view := range a.compareViews {
+			if i >= len(results) {
+				break
+			}
+			text := tview.Escape(results[i].Value)
+			if !results[i].IsValid {
+				text = fmt.Sprintf("[%s]%s[-]", a.theme.ColorError, tview.Escape(results[i].Error))
+			}
+			view.SetText(text)
+		}
+	case CompareCombined:
+		a.outputPanel.SetText(tview.Escape(query.CombineResults(a.documentNames(), results)))
+	}
+}
@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDocumentHistory(t *testing.T) {
+	doc := newDocumentState(Document{Name: "a.json", JSONData: `{"a": 1}`})
+
+	doc.recordHistory("a")
+	doc.recordHistory("b")
+	doc.recordHistory("b") // Repeated entries are skipped
+	doc.recordHistory("")  // Empty entries are skipped
+
+	if len(doc.history) != 2 {
+		t.Fatalf("Expected 2 history entries, got %d: %v", len(doc.history), doc.history)
+	}
+
+	if q, ok := doc.previousHistory(); !ok || q != "b" {
+		t.Errorf("Expected previous history 'b', got %q (%v)", q, ok)
+	}
+	if q, ok := doc.previousHistory(); !ok || q != "a" {
+		t.Errorf("Expected previous history 'a', got %q (%v)", q, ok)
+	}
+	if _, ok := doc.previousHistory(); ok {
+		t.Error("Expected no history before the oldest entry")
+	}
+
+	if q, ok := doc.nextHistory(); !ok || q != "b" {
+		t.Errorf("Expected next history 'b', got %q (%v)", q, ok)
+	}
+	if q, ok := doc.nextHistory(); !ok || q != "" {
+		t.Errorf("Expected empty query after newest entry, got %q (%v)", q, ok)
+	}
+	if _, ok := doc.nextHistory(); ok {
+		t.Error("Expected no history after the newest entry")
+	}
+}
+
+func TestSwitchDocumentKeepsQuery(t *testing.T) {
+	app := NewApp([]Document{
+		{Name: "a.json", JSONData: `{"name": "alpha"}`},
+		{Name: "b.json", JSONData: `{"name": "beta", "extra": true}`},
+	})
+
+	app.inputField.SetText("name")
+	if got := app.outputPanel.GetText(false); got != "alpha" {
+		t.Fatalf("Expected 'alpha' in output, got %q", got)
+	}
+
+	app.nextDocument()
+	if app.activeDocument != 1 {
+		t.Fatalf("Expected second document to be active, got %d", app.activeDocument)
+	}
+	if app.inputField.GetText() != "" {
+		t.Errorf("Expected empty query for new document, got %q", app.inputField.GetText())
+	}
+
+	app.inputField.SetText("extra")
+	app.previousDocument()
+	if app.inputField.GetText() != "name" {
+		t.Errorf("Expected query 'name' to be restored, got %q", app.inputField.GetText())
+	}
+	if got := app.outputPanel.GetText(false); got != "alpha" {
+		t.Errorf("Expected 'alpha' in output after switching back, got %q", got)
+	}
+
+	app.nextDocument()
+	if app.inputField.GetText() != "extra" {
+		t.Errorf("Expected query 'extra' to be restored, got %q", app.inputField.GetText())
+	}
+}
+
+func TestCompareModeCombined(t *testing.T) {
+	app := NewApp([]Document{
+		{Name: "a.json", JSONData: `{"name": "alpha"}`},
+		{Name: "b.json", JSONData: `{"name": "beta"}`},
+	})
+
+	app.cycleCompareMode() // side by side
+	app.inputField.SetText("name")
+	if got := app.compareViews[1].GetText(false); got != "beta" {
+		t.Errorf("Expected 'beta' in second compare view, got %q", got)
+	}
+
+	app.cycleCompareMode() // combined
+	got := app.outputPanel.GetText(false)
+	if !strings.Contains(got, `"a.json": "alpha"`) || !strings.Contains(got, `"b.json": "beta"`) {
+		t.Errorf("Expected combined result, got %q", got)
+	}
+
+	// Values are shown as they are, not as markup
+	app.documents[0].queryEngine.SetData(`{"name": "[red]x"}`)
+	app.documents[1].queryEngine.SetData(`{"name": ["a"]}`)
+	app.runQuery("name")
+	got = app.outputPanel.GetText(true)
+	if !strings.Contains(got, `"a.json": "[red]x"`) || !strings.Contains(got, `"a"`) {
+		t.Errorf("Expected the values unchanged, got %q", got)
+	}
+
+	app.cycleCompareMode() // off
+	if app.compareMode != CompareOff {
+		t.Errorf("Expected compare mode to cycle back to off, got %v", app.compareMode)
+	}
+}
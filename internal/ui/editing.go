@@ -0,0 +1,296 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gataky/dive/internal/export"
+	"github.com/gataky/dive/internal/query"
+	"github.com/rivo/tview"
+	"github.com/tidwall/gjson"
+)
+
+// toggleEditMode turns the keys that change the active document on or off
+func (a *App) toggleEditMode() {
+	if a.diffMode {
+		a.showMessage("Documents can't be edited in the diff view", true)
+		return
+	}
+
+	a.editMode = !a.editMode
+	a.rebuildLayout()
+	a.updateTabBar()
+	if a.editMode {
+		a.showMessage("Edit mode: change the value under the cursor", false)
+	} else {
+		a.showMessage("Edit mode off", false)
+	}
+}
+
+// editing wraps a method that changes the document so it only runs in edit mode
+func editing(f func(a *App)) func(a *App) bool {
+	return func(a *App) bool {
+		if !a.editMode {
+			a.showMessage("Turn on edit mode to change the document", true)
+			return true
+		}
+		f(a)
+		return true
+	}
+}
+
+// editTarget returns the path of the value to edit, which is the value under
+// the cursor or else the current result, and whether it is the current result
+func (a *App) editTarget() (string, bool) {
+	current := a.currentDocument().queryEngine.GetLastValidPath()
+	if a.cursorActive() {
+		path := a.cursorPath()
+		return path, path == current
+	}
+	return current, true
+}
+
+// parseValue returns text as raw JSON. Text that isn't valid JSON is taken
+// as a string, so strings can be entered without quotes.
+func parseValue(text string) string {
+	if gjson.Valid(text) {
+		return text
+	}
+	quoted, _ := json.Marshal(text)
+	return string(quoted)
+}
+
+// editValue asks for a new value for the target
+func (a *App) editValue() {
+	path, current := a.editTarget()
+	doc := a.currentDocument()
+	value := gjson.Get(doc.jsonData, path)
+	if path == "" {
+		value = gjson.Parse(doc.jsonData)
+	}
+	// Objects and arrays are edited on one line
+	text := strings.TrimSpace(value.Raw)
+	if value.IsObject() || value.IsArray() {
+		text = gjson.Get(text, "@ugly").Raw
+	}
+
+	a.showPrompt(" Edit Value ", "Value: ", text, func(text string) {
+		edited, err := query.Set(doc.jsonData, path, parseValue(text))
+		if err != nil {
+			a.showMessage("Error: "+tview.Escape(err.Error()), true)
+			return
+		}
+		a.applyEdit(edited, a.followPath(path, current))
+	})
+}
+
+// renameKey asks for a new key for the target
+func (a *App) renameKey() {
+	path, current := a.editTarget()
+	_, key := query.ParentPath(path)
+
+	a.showPrompt(" Rename Key ", "Key: ", unescapeKey(key), func(key string) {
+		edited, renamed, err := query.Rename(a.currentDocument().jsonData, path, key)
+		if err != nil {
+			a.showMessage("Error: "+tview.Escape(err.Error()), true)
+			return
+		}
+		a.applyEdit(edited, a.followPath(renamed, current))
+	})
+}
+
+// unescapeKey removes the backslashes gjson paths escape special characters with
+func unescapeKey(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		if key[i] == '\\' && i+1 < len(key) {
+			i++
+		}
+		b.WriteByte(key[i])
+	}
+	return b.String()
+}
+
+// appendValue asks for a value to add at the end of the target object or array
+func (a *App) appendValue() {
+	path, current := a.editTarget()
+	doc := a.currentDocument()
+	target := gjson.Get(doc.jsonData, path)
+	if path == "" {
+		target = gjson.Parse(doc.jsonData)
+	}
+
+	label := "Value: "
+	switch {
+	case target.IsObject():
+		label = `"key": value `
+	case !target.IsArray():
+		a.showMessage("Values can only be added to objects and arrays", true)
+		return
+	}
+
+	a.showPrompt(" Add Value ", label, "", func(text string) {
+		if target.IsArray() {
+			text = parseValue(text)
+		}
+		edited, _, err := query.Append(doc.jsonData, path, text)
+		if err != nil {
+			a.showMessage("Error: "+tview.Escape(err.Error()), true)
+			return
+		}
+		a.applyEdit(edited, a.followPath(path, current))
+	})
+}
+
+// insertValue asks for a value to insert after the target array element
+func (a *App) insertValue() {
+	path, current := a.editTarget()
+	a.showPrompt(" Insert Value ", "Value: ", "", func(text string) {
+		edited, inserted, err := query.InsertAfter(a.currentDocument().jsonData, path, parseValue(text))
+		if err != nil {
+			a.showMessage("Error: "+tview.Escape(err.Error()), true)
+			return
+		}
+		a.applyEdit(edited, a.followPath(inserted, current))
+	})
+}
+
+// deleteValue removes the target from its object or array
+func (a *App) deleteValue() {
+	path, current := a.editTarget()
+	edited, err := query.Delete(a.currentDocument().jsonData, path)
+	if err != nil {
+		a.showMessage("Error: "+tview.Escape(err.Error()), true)
+		return
+	}
+	parent, _ := query.ParentPath(path)
+	a.applyEdit(edited, a.followPath(parent, current))
+}
+
+// followPath returns the path to show after an edit: path if the edit
+// changed the current result, or else the current path
+func (a *App) followPath(path string, current bool) string {
+	if current {
+		return path
+	}
+	return a.inputField.GetText()
+}
+
+// applyEdit makes edited the content of the active document, remembering the
+// previous content for undo, and shows path
+func (a *App) applyEdit(edited, path string) {
+	doc := a.currentDocument()
+	doc.changes.undo = append(doc.changes.undo, doc.jsonData)
+	doc.changes.redo = doc.changes.redo[:0]
+	a.setDocumentData(edited, path)
+}
+
+// undoChange restores the content of the active document before the last change
+func (a *App) undoChange() {
+	changes := &a.currentDocument().changes
+	data, ok := moveText(&changes.undo, &changes.redo, a.currentDocument().jsonData)
+	if !ok {
+		a.showMessage("Already at the oldest change", true)
+		return
+	}
+	a.setDocumentData(data, a.inputField.GetText())
+}
+
+// redoChange restores the content of the active document undone last
+func (a *App) redoChange() {
+	changes := &a.currentDocument().changes
+	data, ok := moveText(&changes.redo, &changes.undo, a.currentDocument().jsonData)
+	if !ok {
+		a.showMessage("Already at the newest change", true)
+		return
+	}
+	a.setDocumentData(data, a.inputField.GetText())
+}
+
+// setDocumentData replaces the content of the active document and shows the
+// result of path in it
+func (a *App) setDocumentData(data, path string) {
+	doc := a.currentDocument()
+	doc.jsonData = data
+	doc.queryEngine.SetData(data)
+	a.quitWarned = false
+
+	if path != a.inputField.GetText() {
+		a.navigate(path)
+	} else {
+		a.runQuery(path)
+	}
+	if a.split.visible {
+		// The split pane always shows the active document
+		a.split.engine = a.newSplitEngine(data)
+		a.split.rightValue = a.split.engine.Query(a.split.input.GetText()).Value
+		a.renderSplit()
+	}
+	if a.violationsVisible {
+		a.validateDocument()
+	}
+	a.updateTabBar()
+}
+
+// saveDocument writes the active document back to its file
+func (a *App) saveDocument() {
+	doc := a.currentDocument()
+	if doc.path == "" {
+		a.saveDocumentAs()
+		return
+	}
+	a.writeDocument(doc.path)
+}
+
+// saveDocumentAs asks for a file and writes the active document to it. The
+// document is saved to that file from then on.
+func (a *App) saveDocumentAs() {
+	a.showPrompt(" Save Document ", "Save to file: ", a.currentDocument().path, a.writeDocument)
+}
+
+// writeDocument writes the active document to a file, replacing it atomically.
+// Compressed files and files using JSONC/JSON5 syntax are not overwritten with
+// plain JSON.
+func (a *App) writeDocument(path string) {
+	doc := a.currentDocument()
+	var problem string
+	switch {
+	case path != doc.path:
+	case doc.compression != nil:
+		problem = fmt.Sprintf("%s is %s compressed", tview.Escape(path), doc.compression.Format)
+	case len(doc.relaxed) > 0:
+		problem = fmt.Sprintf("%s has %s that saving would drop", tview.Escape(path), strings.Join(doc.relaxed, ", "))
+	}
+	if problem != "" {
+		a.showMessage(fmt.Sprintf("%s, press %s to save the document to a new file", problem, a.keys.label("save_document_as")), true)
+		return
+	}
+	if err := export.WriteFileAtomic(path, doc.jsonData); err != nil {
+		a.showMessage("Error: "+tview.Escape(err.Error()), true)
+		return
+	}
+	if doc.path != path {
+		doc.path, doc.name = path, path
+	}
+	doc.saved = doc.jsonData
+	if doc.compression != nil || doc.relaxed != nil {
+		doc.compression, doc.relaxed = nil, nil
+		a.originalFooterText = a.footerText()
+	}
+	a.updateTabBar()
+	a.showMessage(fmt.Sprintf("Saved to %s", tview.Escape(path)), false)
+}
+
+// quit stops the application unless a document has unsaved changes, which
+// is only warned about once
+func (a *App) quit() {
+	for _, doc := range a.documents {
+		if doc.modified() && !a.quitWarned {
+			a.quitWarned = true
+			a.showMessage(fmt.Sprintf("%s has unsaved changes, quit again to discard them", tview.Escape(doc.name)), true)
+			return
+		}
+	}
+	a.Stop()
+}
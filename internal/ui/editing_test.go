@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gataky/dive/internal/input"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// submitPrompt enters text in the prompt that has focus and presses Enter
+func submitPrompt(t *testing.T, app *App, text string) {
+	t.Helper()
+	prompt, ok := app.tviewApp.GetFocus().(*tview.InputField)
+	if !ok {
+		t.Fatal("Expected a prompt to have focus")
+	}
+	prompt.SetText(text)
+	prompt.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(tview.Primitive) {})
+}
+
+func TestEditMode(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "doc.json")
+	data := `{"users": [{"name": "Alice"}], "count": 1}`
+	if err := os.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+
+	app := NewApp([]Document{{Name: "doc.json", Path: file, JSONData: data}})
+	app.tviewApp.SetFocus(app.outputPanel)
+	press := func(r rune) {
+		app.dispatch(contextOutput, tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+
+	// Editing keys do nothing until edit mode is turned on
+	press('d')
+	if app.currentDocument().modified() {
+		t.Fatal("Expected the document to be unchanged outside edit mode")
+	}
+	app.dispatch(contextGlobal, tcell.NewEventKey(tcell.KeyF11, 0, tcell.ModNone))
+	if !app.editMode {
+		t.Fatal("Expected F11 to turn on edit mode")
+	}
+
+	// Keys are shown sorted, so line 4 is the name of the first user
+	app.moveCursor(4)
+	press('e')
+	submitPrompt(t, app, "Bob")
+	app.moveCursor(4)
+	press('r')
+	submitPrompt(t, app, "first name")
+	app.inputField.SetText("users")
+	press('a')
+	submitPrompt(t, app, "42")
+
+	expected := `{"users": [{"first name": "Bob"}, 42], "count": 1}`
+	if doc := app.currentDocument(); doc.jsonData != expected {
+		t.Errorf("Expected the edited document %s, got %s", expected, doc.jsonData)
+	}
+	if !strings.Contains(app.tabBar.GetText(true), "doc.json*") {
+		t.Errorf("Expected the tab to be marked as modified, got %q", app.tabBar.GetText(true))
+	}
+
+	// Deleting the current result goes up to its parent
+	app.inputField.SetText("count")
+	press('d')
+	if app.inputField.GetText() != "" || strings.Contains(app.currentDocument().jsonData, "count") {
+		t.Errorf("Expected count to be deleted, got %s at %q", app.currentDocument().jsonData, app.inputField.GetText())
+	}
+	press('u')
+	if !strings.Contains(app.currentDocument().jsonData, `"count": 1`) {
+		t.Errorf("Expected undo to restore count, got %s", app.currentDocument().jsonData)
+	}
+	app.dispatch(contextOutput, tcell.NewEventKey(tcell.KeyRune, 'U', tcell.ModShift))
+	if strings.Contains(app.currentDocument().jsonData, "count") {
+		t.Errorf("Expected redo to delete count again, got %s", app.currentDocument().jsonData)
+	}
+
+	// Quitting with unsaved changes only warns the first time
+	app.quit()
+	if !app.quitWarned {
+		t.Error("Expected quitting with unsaved changes to warn")
+	}
+
+	press('w')
+	saved, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(saved) != app.currentDocument().jsonData || app.currentDocument().modified() {
+		t.Errorf("Expected the document to be saved, got %s", saved)
+	}
+}
+
+func TestEditComputedValue(t *testing.T) {
+	data := `{"users": [{"name": "Alice"}, {"name": "Bob"}]}`
+	app := NewApp([]Document{{Name: "stdin", JSONData: data}})
+	app.editMode = true
+
+	app.inputField.SetText("users.#.name")
+	app.deleteValue()
+	if app.currentDocument().jsonData != data {
+		t.Errorf("Expected a computed result not to be deleted, got %s", app.currentDocument().jsonData)
+	}
+
+	// A document read from stdin is saved to a new file
+	file := filepath.Join(t.TempDir(), "users.json")
+	app.saveDocument()
+	submitPrompt(t, app, file)
+	if doc := app.currentDocument(); doc.path != file || doc.name != file {
+		t.Errorf("Expected the document to be saved to %s, got %s", file, doc.path)
+	}
+}
+
+func TestSaveCompressedDocument(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "doc.json.gz")
+	compression := &input.Compression{Format: "gzip", CompressedSize: 30, Size: 2048}
+	app := NewApp([]Document{
+		{Name: "doc.json.gz", Path: file, JSONData: `{"a": 1}`, Compression: compression},
+		{Name: "other.json", JSONData: `{}`},
+	})
+	if text := app.footer.GetText(true); !strings.Contains(text, "gzip 30 B → 2.0 KiB") {
+		t.Errorf("Expected the footer to show the compression, got %q", text)
+	}
+	app.nextDocument()
+	if text := app.footer.GetText(true); strings.Contains(text, "gzip") {
+		t.Errorf("Expected no compression for an uncompressed document, got %q", text)
+	}
+	app.previousDocument()
+
+	// The compressed file is not overwritten with plain JSON
+	app.editMode = true
+	app.saveDocument()
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Errorf("Expected the compressed file not to be written, got %v", err)
+	}
+
+	plain := filepath.Join(t.TempDir(), "doc.json")
+	app.writeDocument(plain)
+	if app.currentDocument().compression != nil || strings.Contains(app.originalFooterText, "gzip") {
+		t.Error("Expected a document saved uncompressed to no longer show its compression")
+	}
+}
+
+func TestSaveRelaxedDocument(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "settings.jsonc")
+	original := "{\n  // Font\n  size: 14,\n}\n"
+	if err := os.WriteFile(file, []byte(original), 0644); err != nil {
+		t.Fatalf("Failed to create file: %v", err)
+	}
+	app := NewApp([]Document{{Name: "settings.jsonc", Path: file, JSONData: `{"size": 14}`, Relaxed: []string{"comments", "unquoted keys"}}})
+	if text := app.footer.GetText(true); !strings.Contains(text, "Relaxed: comments, unquoted keys") {
+		t.Errorf("Expected the footer to show the relaxed syntax, got %q", text)
+	}
+
+	// Saving in place would drop the comments
+	app.saveDocument()
+	if saved, _ := os.ReadFile(file); string(saved) != original {
+		t.Errorf("Expected the file not to be overwritten, got %s", saved)
+	}
+}
@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gataky/dive/internal/query"
+	"github.com/rivo/tview"
+	"github.com/tidwall/gjson"
+)
+
+// editorCommand returns the command starting the user's editor: $VISUAL,
+// $EDITOR or else vi
+func editorCommand() []string {
+	for _, name := range []string{"VISUAL", "EDITOR"} {
+		if command := strings.Fields(os.Getenv(name)); len(command) > 0 {
+			return command
+		}
+	}
+	return []string{"vi"}
+}
+
+// runEditor edits a file in the user's editor while the terminal UI is suspended
+var runEditor = func(app *tview.Application, file string) error {
+	command := append(editorCommand(), file)
+	var err error
+	suspended := app.Suspend(func() {
+		cmd := exec.Command(command[0], command[1:]...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		err = cmd.Run()
+	})
+	if !suspended {
+		return fmt.Errorf("the terminal can't be handed over to %s", command[0])
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", command[0], err)
+	}
+	return nil
+}
+
+// editInEditor opens the current result in the user's editor and puts the
+// edited JSON in its place in the document
+func (a *App) editInEditor() {
+	doc := a.currentDocument()
+	path := doc.queryEngine.GetLastValidPath()
+	// Only values stored in the document can be replaced
+	if _, err := query.Set(doc.jsonData, path, "null"); err != nil {
+		a.showMessage("Error: "+tview.Escape(err.Error()), true)
+		return
+	}
+
+	var value bytes.Buffer
+	if err := json.Indent(&value, []byte(doc.queryEngine.GetLastValidRaw()), "", query.Indent); err != nil {
+		a.showMessage("Error: "+tview.Escape(err.Error()), true)
+		return
+	}
+	value.WriteByte('\n')
+
+	file, err := os.CreateTemp("", "dive-*.json")
+	if err == nil {
+		_, err = file.Write(value.Bytes())
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		a.showMessage("Error: "+tview.Escape(err.Error()), true)
+		return
+	}
+	a.editFile(file.Name(), path, value.String())
+}
+
+// editFile runs the editor on a file holding the value at path and puts its
+// content in place of the value. Invalid JSON can be edited again or discarded.
+func (a *App) editFile(file, path, original string) {
+	err := runEditor(a.tviewApp, file)
+	var data []byte
+	if err == nil {
+		data, err = os.ReadFile(file)
+	}
+	if err != nil {
+		os.Remove(file)
+		a.showMessage("Error: "+tview.Escape(err.Error()), true)
+		return
+	}
+
+	edited := strings.TrimSpace(string(data))
+	if problem := syntaxError(edited); problem != "" {
+		a.showRetry("Invalid JSON: "+problem, func(retry bool) {
+			if retry {
+				a.editFile(file, path, original)
+			} else {
+				os.Remove(file)
+			}
+		})
+		return
+	}
+	os.Remove(file)
+
+	if edited == strings.TrimSpace(original) {
+		a.showMessage("No changes", false)
+		return
+	}
+	doc := a.currentDocument()
+	changed, err := query.Set(doc.jsonData, path, edited)
+	if err != nil {
+		a.showMessage("Error: "+tview.Escape(err.Error()), true)
+		return
+	}
+	a.applyEdit(changed, path)
+}
+
+// syntaxError describes where JSON is invalid, or returns "" if it is valid
+func syntaxError(data string) string {
+	if gjson.Valid(data) {
+		return ""
+	}
+	var value any
+	err := json.Unmarshal([]byte(data), &value)
+	var syntax *json.SyntaxError
+	switch {
+	case err == nil:
+		return ""
+	case !errors.As(err, &syntax):
+		return err.Error()
+	}
+	line := strings.Count(data[:syntax.Offset], "\n") + 1
+	return fmt.Sprintf("line %d: %s", line, syntax.Error())
+}
+
+// showRetry asks whether to edit again after an error and calls onDone with
+// the answer
+func (a *App) showRetry(message string, onDone func(retry bool)) {
+	modal := tview.NewModal().
+		SetText(message).
+		AddButtons([]string{"Edit again", "Discard"})
+	modal.SetBackgroundColor(a.theme.Background).
+		SetTextColor(a.theme.ColorError).
+		SetBorderColor(a.theme.BorderInvalid)
+
+	previous := a.tviewApp.GetFocus()
+	modal.SetDoneFunc(func(index int, label string) {
+		a.restoreLayout(previous)
+		onDone(index == 0)
+	})
+
+	pages := tview.NewPages().
+		AddPage("main", a.layout, true, true).
+		AddPage("retry", modal, true, true)
+	a.tviewApp.SetRoot(pages, true)
+	a.tviewApp.SetFocus(modal)
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestEditInEditor(t *testing.T) {
+	app := NewApp([]Document{{Name: "doc.json", JSONData: `{"users": [{"name": "Alice"}], "count": 1}`}})
+	app.editMode = true
+	app.inputField.SetText("users.0")
+
+	// The editor replaces the file with each of these in turn
+	var opened []string
+	edits := []string{`{"name": "Bob",}`, `{"name": "Bob"}`}
+	defer func(run func(*tview.Application, string) error) { runEditor = run }(runEditor)
+	runEditor = func(_ *tview.Application, file string) error {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		opened = append(opened, string(data))
+		return os.WriteFile(file, []byte(edits[len(opened)-1]), 0644)
+	}
+
+	app.dispatch(contextOutput, tcell.NewEventKey(tcell.KeyRune, 'E', tcell.ModShift))
+	if len(opened) != 1 || opened[0] != "{\n  \"name\": \"Alice\"\n}\n" {
+		t.Fatalf("Expected the editor to open the current result, got %q", opened)
+	}
+
+	// Invalid JSON offers to edit it again
+	button, ok := app.tviewApp.GetFocus().(*tview.Button)
+	if !ok || button.GetLabel() != "Edit again" {
+		t.Fatalf("Expected the invalid JSON to be reported, got %T", app.tviewApp.GetFocus())
+	}
+	button.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), func(tview.Primitive) {})
+	if len(opened) != 2 || opened[1] != edits[0] {
+		t.Fatalf("Expected the editor to reopen the invalid JSON, got %q", opened)
+	}
+
+	expected := `{"users": [{"name": "Bob"}], "count": 1}`
+	if data := app.currentDocument().jsonData; data != expected {
+		t.Errorf("Expected the edited value in the document %s, got %s", expected, data)
+	}
+}
+
+func TestSyntaxError(t *testing.T) {
+	if problem := syntaxError(`{"a": 1}`); problem != "" {
+		t.Errorf("Expected valid JSON to have no error, got %q", problem)
+	}
+	if problem := syntaxError("{\n  \"a\": 1,\n}"); !strings.HasPrefix(problem, "line 3: ") {
+		t.Errorf("Expected the error on line 3, got %q", problem)
+	}
+}
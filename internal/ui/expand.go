@@ -0,0 +1,54 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/gataky/dive/internal/query"
+	"github.com/rivo/tview"
+)
+
+// toggleExpandStrings turns traversal of strings holding JSON on or off for
+// every document
+func (a *App) toggleExpandStrings() {
+	a.expandStrings = !a.expandStrings
+	for _, doc := range a.documents {
+		doc.queryEngine.SetExpandStrings(a.expandStrings)
+	}
+	if a.split.engine != nil {
+		a.split.engine.SetExpandStrings(a.expandStrings)
+	}
+
+	path := a.inputField.GetText()
+	a.runQuery(path)
+	if a.split.visible {
+		a.split.rightValue = a.split.engine.Query(a.split.input.GetText()).Value
+		a.renderSplit()
+	}
+	if a.dropdownVisible {
+		a.updateSuggestionsFor(a.dropdownTarget)
+	}
+
+	if a.expandStrings {
+		a.showMessage("Paths traverse strings that hold JSON", false)
+	} else {
+		a.showMessage("Strings that hold JSON are no longer expanded", false)
+	}
+}
+
+// newSplitEngine creates the split pane's query engine for data, expanding
+// strings if turned on
+func (a *App) newSplitEngine(data string) *query.Engine {
+	engine := query.NewEngine(data)
+	engine.SetExpandStrings(a.expandStrings)
+	a.runPluginsInBackground(engine, a.splitPluginFinished)
+	return engine
+}
+
+// expandedTitle marks a result whose path went through strings holding JSON
+// with the paths of those strings
+func expandedTitle(expanded []string) string {
+	if len(expanded) == 0 {
+		return ""
+	}
+	return " Expanded JSON strings: " + tview.Escape(strings.Join(expanded, ", ")) + " "
+}
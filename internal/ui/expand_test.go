@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestExpandStrings(t *testing.T) {
+	data := `{"events": [{"body": "{\"user\": {\"id\": 7}}"}]}`
+	app := NewApp([]Document{{Name: "events.json", JSONData: data}})
+
+	app.inputField.SetText("events.0.body.user")
+	if app.currentDocument().queryEngine.GetLastValidPath() != "" {
+		t.Fatal("Expected the path not to traverse the string by default")
+	}
+
+	app.dispatch(contextGlobal, tcell.NewEventKey(tcell.KeyCtrlG, 0, tcell.ModCtrl))
+	if !app.expandStrings {
+		t.Fatal("Expected Ctrl+G to expand strings")
+	}
+	if path := app.currentDocument().queryEngine.GetLastValidPath(); path != "events.0.body.user" {
+		t.Errorf("Expected the path to traverse the string, got %q", path)
+	}
+	if title := app.outputPanel.GetTitle(); !strings.Contains(title, "Expanded JSON strings: events.0.body") {
+		t.Errorf("Expected the output to be marked as expanded, got %q", title)
+	}
+
+	app.inputField.SetText("events.0.body.")
+	app.updateSuggestions()
+	if count := app.autocompleteDropdown.GetItemCount(); count != 1 {
+		t.Errorf("Expected the key inside the string to be suggested, got %d suggestions", count)
+	}
+
+	// Documents opened later expand strings too
+	app.openDocument(Document{Name: "copy.json", JSONData: data})
+	app.inputField.SetText("events.0.body.user.id")
+	if value := app.currentDocument().queryEngine.GetLastValidValue(); value != "7" {
+		t.Errorf("Expected the new document to expand strings, got %q", value)
+	}
+
+	app.toggleExpandStrings()
+	if title := app.outputPanel.GetTitle(); title != "" {
+		t.Errorf("Expected the mark to be removed, got %q", title)
+	}
+}
@@ -3,10 +3,12 @@ package ui
 import (
 	"strings"
 	"testing"
+
+	"github.com/gataky/dive/internal/ui/theme"
 )
 
 func TestGetHelpContent(t *testing.T) {
-	content := getHelpContent()
+	content := getHelpContent(theme.DefaultTheme())
 
 	// Test that content is not empty
 	if content == "" {
@@ -20,7 +22,7 @@ func TestGetHelpContent(t *testing.T) {
 }
 
 func TestGetHelpContentSections(t *testing.T) {
-	content := getHelpContent()
+	content := getHelpContent(theme.DefaultTheme())
 
 	// Test that all three complexity level sections are present
 	requiredSections := []string{
@@ -37,7 +39,7 @@ func TestGetHelpContentSections(t *testing.T) {
 }
 
 func TestGetHelpContentSyntaxExamples(t *testing.T) {
-	content := getHelpContent()
+	content := getHelpContent(theme.DefaultTheme())
 
 	// Test that expected syntax examples are present
 	expectedExamples := []string{
@@ -0,0 +1,244 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// Size of the history popup
+const (
+	historyWidth  = 76
+	historyHeight = 24
+)
+
+// pathHistory is the list of paths visited in a document, which is browsed
+// back and forward like the history of a web browser
+type pathHistory struct {
+	paths []string
+	pos   int // Index of the current path
+}
+
+// visit makes path the current path, dropping the paths after the previous
+// current path
+func (h *pathHistory) visit(path string) {
+	if len(h.paths) > 0 && h.paths[h.pos] == path {
+		return
+	}
+	if len(h.paths) > 0 {
+		h.paths = h.paths[:h.pos+1]
+	}
+	h.paths = append(h.paths, path)
+	h.pos = len(h.paths) - 1
+}
+
+// move returns the path offset entries away from the current one and makes
+// it current, if there is one
+func (h *pathHistory) move(offset int) (string, bool) {
+	pos := h.pos + offset
+	if pos < 0 || pos >= len(h.paths) {
+		return "", false
+	}
+	h.pos = pos
+	return h.paths[pos], true
+}
+
+// editHistory holds the texts of an input field before and after the current
+// text for undo and redo
+type editHistory struct {
+	undo   []string // Older texts, the latest last
+	redo   []string // Undone texts, the latest undone last
+	typing bool     // Whether the last edit typed or deleted a character
+}
+
+// record remembers the text before an edit, ignoring texts that are set
+// again unchanged. Characters typed one after another
+// are undone together, up to a separator so each level of a path is undone
+// on its own.
+func (e *editHistory) record(previous, text string) {
+	if previous == text {
+		return
+	}
+	typing := len(text)-len(previous) == 1 && strings.HasPrefix(text, previous) ||
+		len(previous)-len(text) == 1 && strings.HasPrefix(previous, text)
+	if !typing || !e.typing || strings.HasSuffix(text, ".") || strings.HasSuffix(text, "|") {
+		e.undo = append(e.undo, previous)
+	}
+	e.redo = e.redo[:0]
+	e.typing = typing
+}
+
+// moveText pops a text from one stack and pushes current onto the other, and
+// returns the popped text
+func moveText(from, to *[]string, current string) (string, bool) {
+	if len(*from) == 0 {
+		return "", false
+	}
+	text := (*from)[len(*from)-1]
+	*from = (*from)[:len(*from)-1]
+	*to = append(*to, current)
+	return text, true
+}
+
+// historyPopup holds the components of the popup listing visited paths and edits
+type historyPopup struct {
+	visible bool
+	view    *tview.Flex
+	visits  *tview.List
+	edits   *tview.List
+	focus   tview.Primitive // Component focused before the popup opened
+}
+
+// initHistoryPopup creates the components of the history popup
+func (a *App) initHistoryPopup() {
+	a.history.visits = createAutocompleteDropdown(a.theme)
+	a.history.edits = createAutocompleteDropdown(a.theme)
+	a.history.view = tview.NewFlex().SetDirection(tview.FlexRow)
+	for _, list := range []*tview.List{a.history.visits, a.history.edits} {
+		list.SetBorder(true)
+		list.SetInputCapture(a.captureKeys(contextHistory))
+		a.history.view.AddItem(list, 0, 1, true)
+	}
+	a.history.visits.SetTitle(" Visited paths ")
+	a.history.edits.SetTitle(" Edits ")
+}
+
+// toggleHistory opens the popup listing the visited paths and the edits of
+// the path, or closes it if it is open
+func (a *App) toggleHistory() {
+	if a.history.visible {
+		a.hideHistory()
+		return
+	}
+
+	doc := a.currentDocument()
+	doc.visits.visit(doc.queryEngine.GetLastValidPath())
+	a.fillHistory()
+
+	a.history.visible = true
+	a.history.focus = a.tviewApp.GetFocus()
+	pages := tview.NewPages().
+		AddPage("main", a.layout, true, true).
+		AddPage("history", centered(a.history.view, historyWidth, historyHeight), true, true)
+	a.tviewApp.SetRoot(pages, true)
+	a.tviewApp.SetFocus(a.history.visits)
+}
+
+// hideHistory closes the history popup and focuses the component that had focus before
+func (a *App) hideHistory() {
+	a.history.visible = false
+	a.restoreLayout(a.history.focus)
+}
+
+// fillHistory lists the visited paths and the texts of the input field from
+// the oldest to the newest, with the current ones selected
+func (a *App) fillHistory() {
+	doc := a.currentDocument()
+
+	a.history.visits.Clear()
+	for i, path := range doc.visits.paths {
+		a.history.visits.AddItem(historyItem(path, "(root)", i == doc.visits.pos), "", 0, func() {
+			a.hideHistory()
+			a.goToVisit(i - doc.visits.pos)
+		})
+	}
+	a.history.visits.SetCurrentItem(doc.visits.pos)
+
+	// The redo stack holds the newest text first
+	texts := append(append([]string{}, doc.edits.undo...), a.inputField.GetText())
+	for i := len(doc.edits.redo) - 1; i >= 0; i-- {
+		texts = append(texts, doc.edits.redo[i])
+	}
+	current := len(doc.edits.undo)
+	a.history.edits.Clear()
+	for i, text := range texts {
+		a.history.edits.AddItem(historyItem(text, "(empty)", i == current), "", 0, func() {
+			a.hideHistory()
+			for range current - i {
+				a.undo()
+			}
+			for range i - current {
+				a.redo()
+			}
+		})
+	}
+	a.history.edits.SetCurrentItem(current)
+}
+
+// historyItem formats an entry of the history popup, marking the current one
+func historyItem(text, empty string, current bool) string {
+	if text == "" {
+		text = empty
+	}
+	if current {
+		return "● " + tview.Escape(text)
+	}
+	return "  " + tview.Escape(text)
+}
+
+// switchHistoryList moves the focus between the visited paths and the edits
+func (a *App) switchHistoryList() {
+	if a.tviewApp.GetFocus() == a.history.visits {
+		a.tviewApp.SetFocus(a.history.edits)
+	} else {
+		a.tviewApp.SetFocus(a.history.visits)
+	}
+}
+
+// navigate sets the path of the main input field, remembering the paths
+// before and after as visited
+func (a *App) navigate(path string) {
+	doc := a.currentDocument()
+	doc.visits.visit(doc.queryEngine.GetLastValidPath())
+	a.inputField.SetText(path)
+	doc.visits.visit(doc.queryEngine.GetLastValidPath())
+}
+
+// goToVisit sets the path to the path visited offset entries before or after
+// the current one, like the back and forward buttons of a web browser
+func (a *App) goToVisit(offset int) {
+	doc := a.currentDocument()
+	// A path typed since the last visit can be returned to as well
+	doc.visits.visit(doc.queryEngine.GetLastValidPath())
+
+	path, ok := doc.visits.move(offset)
+	if !ok {
+		if offset < 0 {
+			a.showMessage("No earlier path", true)
+		} else {
+			a.showMessage("No later path", true)
+		}
+		return
+	}
+	a.inputField.SetText(path)
+}
+
+// undo restores the text of the main input field before the last edit
+func (a *App) undo() {
+	edits := &a.currentDocument().edits
+	text, ok := moveText(&edits.undo, &edits.redo, a.inputField.GetText())
+	if !ok {
+		a.showMessage("Already at the oldest edit", true)
+		return
+	}
+	a.restoreText(text)
+}
+
+// redo restores the text of the main input field undone last
+func (a *App) redo() {
+	edits := &a.currentDocument().edits
+	text, ok := moveText(&edits.redo, &edits.undo, a.inputField.GetText())
+	if !ok {
+		a.showMessage("Already at the newest edit", true)
+		return
+	}
+	a.restoreText(text)
+}
+
+// restoreText sets the text of the main input field without recording it as an edit
+func (a *App) restoreText(text string) {
+	a.restoring = true
+	a.inputField.SetText(text)
+	a.restoring = false
+	a.currentDocument().edits.typing = false
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestUndoRedo(t *testing.T) {
+	app := NewApp([]Document{{Name: "doc.json", JSONData: `{"users": [{"name": "Alice"}], "count": 1}`}})
+
+	// Typing is undone a level at a time
+	for _, text := range []string{"u", "us", "use", "user", "users", "users.", "users.0"} {
+		app.inputField.SetText(text)
+	}
+	app.selectSuggestion("count")
+
+	steps := []struct {
+		key      tcell.Key
+		expected string
+	}{
+		{tcell.KeyCtrlZ, "users.0"},
+		{tcell.KeyCtrlZ, "users"},
+		{tcell.KeyCtrlZ, ""},
+		{tcell.KeyCtrlZ, ""},
+		{tcell.KeyCtrlY, "users"},
+		{tcell.KeyCtrlY, "users.0"},
+		{tcell.KeyCtrlY, "count"},
+	}
+	for _, step := range steps {
+		app.dispatch(contextGlobal, tcell.NewEventKey(step.key, 0, tcell.ModCtrl))
+		if text := app.inputField.GetText(); text != step.expected {
+			t.Errorf("Expected %s to restore %q, got %q", tcell.KeyNames[step.key], step.expected, text)
+		}
+	}
+
+	// A new edit drops the texts that could be redone
+	app.dispatch(contextGlobal, tcell.NewEventKey(tcell.KeyCtrlZ, 0, tcell.ModCtrl))
+	app.inputField.SetText("users.0.name")
+	if edits := app.currentDocument().edits; len(edits.redo) != 0 {
+		t.Errorf("Expected no redo after an edit, got %q", edits.redo)
+	}
+}
+
+func TestUndoAfterSwitchingDocuments(t *testing.T) {
+	app := NewApp([]Document{{Name: "a.json", JSONData: `{"x": 1}`}, {Name: "b.json", JSONData: `{}`}})
+
+	app.inputField.SetText("x")
+	app.nextDocument()
+	app.previousDocument()
+	if undo := app.currentDocument().edits.undo; len(undo) != 1 || undo[0] != "" {
+		t.Errorf("Expected switching back not to be recorded as an edit, got %q", undo)
+	}
+
+	app.dispatch(contextGlobal, tcell.NewEventKey(tcell.KeyCtrlZ, 0, tcell.ModCtrl))
+	if text := app.inputField.GetText(); text != "" {
+		t.Errorf("Expected the first undo to remove x, got %q", text)
+	}
+}
+
+func TestBackForward(t *testing.T) {
+	app := NewApp([]Document{{Name: "doc.json", JSONData: `{"users": [{"name": "Alice"}, {"name": "Bob"}]}`}})
+	press := func(r rune) {
+		app.dispatch(contextGlobal, tcell.NewEventKey(tcell.KeyRune, r, tcell.ModAlt))
+	}
+
+	app.inputField.SetText("users")
+	app.navigate("users.0")
+	app.goToSibling(1)
+
+	steps := []struct {
+		key      rune
+		expected string
+	}{
+		{',', "users.0"},
+		{',', "users"},
+		{',', "users"},
+		{'.', "users.0"},
+		{'.', "users.1"},
+		{'.', "users.1"},
+	}
+	for _, step := range steps {
+		press(step.key)
+		if path := app.inputField.GetText(); path != step.expected {
+			t.Errorf("Expected Alt+%c to go to %q, got %q", step.key, step.expected, path)
+		}
+	}
+
+	// Visiting a path after going back drops the later paths
+	press(',')
+	app.navigate("users.0.name")
+	if visits := app.currentDocument().visits.paths; len(visits) != 3 || visits[2] != "users.0.name" {
+		t.Errorf("Expected the later paths to be dropped, got %q", visits)
+	}
+}
+
+func TestHistoryPopup(t *testing.T) {
+	app := NewApp([]Document{{Name: "doc.json", JSONData: `{"users": [{"name": "Alice"}]}`}})
+	app.inputField.SetText("users")
+	app.navigate("users.0")
+	app.undo()
+
+	app.dispatch(contextGlobal, tcell.NewEventKey(tcell.KeyCtrlR, 0, tcell.ModCtrl))
+	if !app.history.visible || app.tviewApp.GetFocus() != app.history.visits {
+		t.Fatal("Expected Ctrl+R to open the history popup")
+	}
+	// Undoing went back to a path, which is now the latest visit
+	if main, _ := app.history.visits.GetItemText(2); main != "● users" {
+		t.Errorf("Expected the current path to be marked, got %q", main)
+	}
+	if count := app.history.edits.GetItemCount(); count != 3 || app.history.edits.GetCurrentItem() != 1 {
+		t.Errorf("Expected three edits with the second current, got %d and %d", count, app.history.edits.GetCurrentItem())
+	}
+
+	app.dispatch(contextHistory, tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone))
+	if app.tviewApp.GetFocus() != app.history.edits {
+		t.Error("Expected Tab to focus the edits")
+	}
+	app.dispatch(contextHistory, tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	if app.history.visible {
+		t.Error("Expected Esc to close the history popup")
+	}
+}
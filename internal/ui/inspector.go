@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gataky/dive/internal/inspect"
+	"github.com/rivo/tview"
+	"github.com/tidwall/gjson"
+)
+
+// inspectorPane holds the pane showing decoded forms of the value under the cursor
+type inspectorPane struct {
+	visible bool
+	view    *tview.TextView
+}
+
+// toggleInspector shows or hides the inspector
+func (a *App) toggleInspector() {
+	a.inspector.visible = !a.inspector.visible
+	a.rebuildLayout()
+	a.updateInspector()
+}
+
+// inspectedValue returns the path and value under the cursor, or of the
+// current result when there is no cursor
+func (a *App) inspectedValue() (string, gjson.Result) {
+	engine := a.currentDocument().queryEngine
+	value := gjson.Parse(engine.GetLastValidRaw())
+	if !a.cursorActive() {
+		return engine.GetLastValidPath(), value
+	}
+	if relative := a.linePaths[a.cursor]; relative != "" {
+		value = value.Get(relative)
+	}
+	return a.cursorPath(), value
+}
+
+// updateInspector shows the decoded forms of the inspected value
+func (a *App) updateInspector() {
+	if !a.inspector.visible {
+		return
+	}
+
+	path, value := a.inspectedValue()
+	if path == "" {
+		path = "@this"
+	}
+	a.inspector.view.SetTitle(" Inspector: " + tview.Escape(path) + " ")
+	a.inspector.view.ScrollToBeginning()
+
+	if value.IsObject() || value.IsArray() {
+		a.inspector.view.SetText(fmt.Sprintf("[%s]Move the cursor to a string or number to decode it[-]", a.theme.TextMuted))
+		return
+	}
+	found := inspect.Inspect(value, time.Local)
+	if len(found) == 0 {
+		a.inspector.view.SetText(fmt.Sprintf("[%s]No encoded data found in this value[-]", a.theme.TextMuted))
+		return
+	}
+
+	var b strings.Builder
+	for i, interpretation := range found {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(&b, "[%s::b]%s[-::-]", a.theme.TextAccent, tview.Escape(interpretation.Kind))
+		if interpretation.JSON != "" {
+			fmt.Fprintf(&b, " [%s](%s to explore)[-]", a.theme.TextMuted, tview.Escape(a.keys.label("explore_decoded")))
+		}
+		b.WriteByte('\n')
+		for _, line := range interpretation.Lines {
+			b.WriteString(tview.Escape(line) + "\n")
+		}
+	}
+	a.inspector.view.SetText(b.String())
+}
+
+// exploreDecoded dives into JSON found in the inspected value. JSON in a
+// string is explored in place, with @fromstr unless strings are expanded.
+// Other JSON such as the claims of a JWT opens as a new document.
+func (a *App) exploreDecoded() {
+	path, value := a.inspectedValue()
+	for _, interpretation := range inspect.Inspect(value, time.Local) {
+		switch {
+		case interpretation.JSON == "":
+			continue
+		case interpretation.Kind == inspect.KindJSONString && path == "":
+			a.navigate("@fromstr")
+		case interpretation.Kind == inspect.KindJSONString && a.expandStrings:
+			a.navigate(path)
+		case interpretation.Kind == inspect.KindJSONString:
+			a.navigate(path + "|@fromstr")
+		default:
+			name := path
+			if name == "" {
+				name = a.currentDocument().name
+			}
+			a.openDocument(Document{Name: fmt.Sprintf("%s (%s)", name, interpretation.Kind), JSONData: interpretation.JSON})
+		}
+		return
+	}
+	a.showMessage("No JSON found in this value", true)
+}
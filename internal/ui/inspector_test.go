@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestInspector(t *testing.T) {
+	app := NewApp([]Document{{Name: "events.json", JSONData: `{"events": [{"at": 1700000000, "body": "{\"id\": 7}"}]}`}})
+	app.dispatch(contextGlobal, tcell.NewEventKey(tcell.KeyF12, 0, tcell.ModNone))
+	if !app.inspector.visible {
+		t.Fatal("Expected F12 to show the inspector")
+	}
+
+	app.inputField.SetText("events.0")
+	if text := app.inspector.view.GetText(true); !strings.Contains(text, "to decode it") {
+		t.Errorf("Expected a hint for objects, got %q", text)
+	}
+
+	// The inspector follows the cursor; line 1 is the timestamp
+	app.tviewApp.SetFocus(app.outputPanel)
+	app.moveCursor(1)
+	if text := app.inspector.view.GetText(true); !strings.Contains(text, "Unix time (seconds)") || !strings.Contains(text, "UTC:   2023-11-14 22:13:20 UTC") {
+		t.Errorf("Expected the timestamp to be decoded, got:\n%s", text)
+	}
+
+	app.moveCursor(2)
+	app.dispatch(contextOutput, tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone))
+	if path := app.inputField.GetText(); path != "events.0.body|@fromstr" {
+		t.Fatalf("Expected x to explore the JSON string, got %q", path)
+	}
+	if value := app.currentDocument().queryEngine.Query(app.cursorPath() + ".id").Value; value != "7" {
+		t.Errorf("Expected the decoded JSON to be queried in place, got %q", value)
+	}
+}
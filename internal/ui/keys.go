@@ -0,0 +1,359 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// keyBinding identifies a key press independently of how the terminal reports it
+type keyBinding struct {
+	key tcell.Key
+	r   rune // Character of tcell.KeyRune bindings
+	mod tcell.ModMask
+}
+
+// keyNames maps lowercase key names to keys, e.g. "f1", "pgup" and "left"
+var keyNames = func() map[string]tcell.Key {
+	names := map[string]tcell.Key{
+		"escape":    tcell.KeyEscape,
+		"pageup":    tcell.KeyPgUp,
+		"pagedown":  tcell.KeyPgDn,
+		"backspace": tcell.KeyBackspace2,
+	}
+	for key, name := range tcell.KeyNames {
+		// Ctrl combinations are written as ctrl+<key>
+		if !strings.HasPrefix(name, "Ctrl-") {
+			if _, ok := names[strings.ToLower(name)]; !ok {
+				names[strings.ToLower(name)] = key
+			}
+		}
+	}
+	return names
+}()
+
+// parseKey parses a key such as "ctrl+q", "f1", "alt+left", "shift+up" or "?"
+func parseKey(s string) (keyBinding, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(s)), "+")
+	base := parts[len(parts)-1]
+	if base == "" && len(parts) > 1 {
+		// "ctrl++" binds the plus key
+		base, parts = "+", parts[:len(parts)-1]
+	}
+
+	var mod tcell.ModMask
+	for _, part := range parts[:len(parts)-1] {
+		switch part {
+		case "ctrl":
+			mod |= tcell.ModCtrl
+		case "alt":
+			mod |= tcell.ModAlt
+		case "shift":
+			mod |= tcell.ModShift
+		default:
+			return keyBinding{}, fmt.Errorf("invalid key %q: unknown modifier %q", s, part)
+		}
+	}
+
+	if base == "space" {
+		base = " "
+	}
+	if utf8.RuneCountInString(base) == 1 {
+		r, _ := utf8.DecodeRuneInString(base)
+		if mod&tcell.ModCtrl != 0 {
+			if r < 'a' || r > 'z' {
+				return keyBinding{}, fmt.Errorf("invalid key %q: only letters can be combined with ctrl", s)
+			}
+			return keyBinding{key: tcell.KeyCtrlA + tcell.Key(r-'a'), mod: mod &^ tcell.ModCtrl}, nil
+		}
+		if mod&tcell.ModShift != 0 {
+			r = unicode.ToUpper(r)
+		}
+		return keyBinding{key: tcell.KeyRune, r: r, mod: mod &^ tcell.ModShift}, nil
+	}
+
+	if base == "tab" && mod == tcell.ModShift {
+		return keyBinding{key: tcell.KeyBacktab}, nil
+	}
+	key, ok := keyNames[base]
+	if !ok {
+		return keyBinding{}, fmt.Errorf("invalid key %q: unknown key %q", s, base)
+	}
+	return keyBinding{key: key, mod: mod}, nil
+}
+
+// eventBinding returns the binding matching a key event. Terminals report
+// Ctrl+letter as its own key and shifted characters as the character itself,
+// so those modifiers are ignored.
+func eventBinding(event *tcell.EventKey) keyBinding {
+	key, mod := event.Key(), event.Modifiers()
+	switch {
+	case key == tcell.KeyRune:
+		return keyBinding{key: key, r: event.Rune(), mod: mod &^ tcell.ModShift}
+	case key >= tcell.KeyCtrlA && key <= tcell.KeyCtrlZ, key == tcell.KeyBacktab:
+		return keyBinding{key: key, mod: mod &^ (tcell.ModCtrl | tcell.ModShift)}
+	}
+	return keyBinding{key: key, mod: mod}
+}
+
+// String returns the key as shown in the footer, e.g. "Ctrl+Q" or "Alt+Left"
+func (b keyBinding) String() string {
+	var prefix string
+	if b.mod&tcell.ModCtrl != 0 {
+		prefix += "Ctrl+"
+	}
+	if b.mod&tcell.ModAlt != 0 {
+		prefix += "Alt+"
+	}
+	if b.mod&tcell.ModShift != 0 {
+		prefix += "Shift+"
+	}
+
+	switch {
+	case b.key == tcell.KeyRune && b.r == ' ':
+		return prefix + "Space"
+	case b.key == tcell.KeyRune:
+		return prefix + string(b.r)
+	case b.key >= tcell.KeyCtrlA && b.key <= tcell.KeyCtrlZ && !strings.HasPrefix(tcell.KeyNames[b.key], "Ctrl-"):
+		// Tab, Enter and Backspace share their codes with Ctrl+I, Ctrl+M and Ctrl+H
+		return prefix + tcell.KeyNames[b.key]
+	case b.key >= tcell.KeyCtrlA && b.key <= tcell.KeyCtrlZ:
+		return prefix + "Ctrl+" + string(rune('A'+b.key-tcell.KeyCtrlA))
+	case b.key == tcell.KeyBacktab:
+		return prefix + "Shift+Tab"
+	case b.key == tcell.KeyBackspace2:
+		return prefix + "Backspace"
+	}
+	return prefix + tcell.KeyNames[b.key]
+}
+
+// keySequence is a key or several keys pressed one after another, e.g. "g g"
+type keySequence []keyBinding
+
+// parseKeySequence parses keys separated by spaces such as "g g" or "ctrl+w v"
+func parseKeySequence(s string) (keySequence, error) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		// Let parseKey report the empty key
+		fields = []string{s}
+	}
+	seq := make(keySequence, len(fields))
+	for i, field := range fields {
+		binding, err := parseKey(field)
+		if err != nil {
+			return nil, err
+		}
+		seq[i] = binding
+	}
+	return seq, nil
+}
+
+// String returns the keys as shown in the footer, e.g. "gg", "Ctrl+W v" or "F1"
+func (seq keySequence) String() string {
+	compact := true
+	names := make([]string, len(seq))
+	for i, binding := range seq {
+		names[i] = binding.String()
+		if binding.key != tcell.KeyRune || binding.mod != tcell.ModNone || binding.r == ' ' {
+			compact = false
+		}
+	}
+	if compact {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names, " ")
+}
+
+// keyNode is a node of the tree of key sequences bound in a context. Either
+// an action is bound to the keys leading to it, or longer sequences continue
+// from it.
+type keyNode struct {
+	action *action
+	seq    keySequence // Keys the action is bound to
+	next   map[keyBinding]*keyNode
+}
+
+// newKeyNode returns an empty node
+func newKeyNode() *keyNode {
+	return &keyNode{next: make(map[keyBinding]*keyNode)}
+}
+
+// first returns the node of an action bound to the node or any sequence continuing from it, or nil
+func (n *keyNode) first() *keyNode {
+	if n.action != nil {
+		return n
+	}
+	for _, next := range n.next {
+		if node := next.first(); node != nil {
+			return node
+		}
+	}
+	return nil
+}
+
+// conflict returns the node of an action bound to seq, to a sequence
+// starting with seq or to the start of seq, or nil
+func (n *keyNode) conflict(seq keySequence) *keyNode {
+	node := n
+	for _, binding := range seq {
+		if node.action != nil {
+			return node
+		}
+		if node = node.next[binding]; node == nil {
+			return nil
+		}
+	}
+	return node.first()
+}
+
+// bind binds an action to seq
+func (n *keyNode) bind(seq keySequence, act *action) {
+	node := n
+	for _, binding := range seq {
+		next := node.next[binding]
+		if next == nil {
+			next = newKeyNode()
+			node.next[binding] = next
+		}
+		node = next
+	}
+	node.action, node.seq = act, seq
+}
+
+// keymap maps key sequences to actions in every context and action names to their keys
+type keymap struct {
+	actions  []*action // In the order of the registry
+	bound    map[keyContext]*keyNode
+	bindings map[string][]keySequence
+}
+
+// newKeymap binds the default keys of every action, replaced by the keys in
+// overrides for the actions it lists. An empty list unbinds an action. With
+// vim set, actions use their vim keys by default. Keys of global actions must
+// not be bound in any other context, as global actions see them first.
+func newKeymap(overrides map[string][]string, vim bool) (*keymap, error) {
+	km := &keymap{
+		actions:  registry(),
+		bound:    make(map[keyContext]*keyNode),
+		bindings: make(map[string][]keySequence),
+	}
+	for _, name := range sortedNames(overrides) {
+		if km.find(name) == nil {
+			return nil, fmt.Errorf("keys.%s: unknown action", name)
+		}
+	}
+	for _, context := range keyContexts {
+		km.bound[context] = newKeyNode()
+	}
+
+	for _, act := range km.actions {
+		keys, ok := overrides[act.name]
+		if !ok {
+			keys = act.defaultKeys(vim)
+		}
+		for _, key := range keys {
+			seq, err := parseKeySequence(key)
+			if err != nil {
+				return nil, fmt.Errorf("keys.%s: %w", act.name, err)
+			}
+			for _, context := range act.contexts {
+				if context.typed() && seq[0].key == tcell.KeyRune && seq[0].mod == tcell.ModNone {
+					return nil, fmt.Errorf("keys.%s: %q could no longer be typed, combine it with ctrl or alt", act.name, key)
+				}
+				if other := km.conflict(context, seq); other != nil {
+					return nil, conflictError(act.name, seq, other, overrides)
+				}
+				km.bound[context].bind(seq, act)
+			}
+			km.bindings[act.name] = append(km.bindings[act.name], seq)
+		}
+	}
+	return km, nil
+}
+
+// conflictError reports that the keys of two actions clash, blaming the
+// action whose keys were configured
+func conflictError(name string, seq keySequence, other *keyNode, overrides map[string][]string) error {
+	bound, boundSeq := other.action.name, other.seq
+	if _, configured := overrides[name]; !configured {
+		name, seq, bound, boundSeq = bound, boundSeq, name, seq
+	}
+	if seq.String() == boundSeq.String() {
+		return fmt.Errorf("keys.%s: %s is already bound to %s", name, seq, bound)
+	}
+	return fmt.Errorf("keys.%s: %s overlaps %s of %s", name, seq, boundSeq, bound)
+}
+
+// conflict returns the node of the action a sequence bound in context would clash with, or nil
+func (km *keymap) conflict(context keyContext, seq keySequence) *keyNode {
+	if other := km.bound[context].conflict(seq); other != nil {
+		return other
+	}
+	if context != contextGlobal {
+		return km.bound[contextGlobal].conflict(seq)
+	}
+	for _, c := range keyContexts {
+		if other := km.bound[c].conflict(seq); other != nil {
+			return other
+		}
+	}
+	return nil
+}
+
+// lookup returns the action bound to a single key event in a context, or nil
+func (km *keymap) lookup(context keyContext, event *tcell.EventKey) *action {
+	if node := km.bound[context].next[eventBinding(event)]; node != nil {
+		return node.action
+	}
+	return nil
+}
+
+// find returns the action with the given name, or nil
+func (km *keymap) find(name string) *action {
+	for _, act := range km.actions {
+		if act.name == name {
+			return act
+		}
+	}
+	return nil
+}
+
+// label returns the keys bound to an action as shown in the footer, or "" if it is unbound
+func (km *keymap) label(action string) string {
+	names := make([]string, len(km.bindings[action]))
+	for i, binding := range km.bindings[action] {
+		names[i] = binding.String()
+	}
+	return strings.Join(names, "/")
+}
+
+// CheckKeys reports the first key binding in the configuration that cannot be used
+func CheckKeys(keys map[string][]string, vim bool) error {
+	_, err := newKeymap(keys, vim)
+	return err
+}
+
+// DefaultKeys returns the default keys of every action that has any
+func DefaultKeys(vim bool) map[string][]string {
+	keys := make(map[string][]string)
+	for _, act := range registry() {
+		if defaults := act.defaultKeys(vim); len(defaults) > 0 {
+			keys[act.name] = append([]string(nil), defaults...)
+		}
+	}
+	return keys
+}
+
+// sortedNames returns the keys of a map in sorted order
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
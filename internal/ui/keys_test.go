@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gataky/dive/internal/config"
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		key      string
+		event    *tcell.EventKey
+		expected string
+	}{
+		{"ctrl+q", tcell.NewEventKey(tcell.KeyCtrlQ, 0, tcell.ModCtrl), "Ctrl+Q"},
+		{"F1", tcell.NewEventKey(tcell.KeyF1, 0, tcell.ModNone), "F1"},
+		{"alt+left", tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModAlt), "Alt+Left"},
+		{"shift+tab", tcell.NewEventKey(tcell.KeyBacktab, 0, tcell.ModShift), "Shift+Tab"},
+		{"alt+shift+x", tcell.NewEventKey(tcell.KeyRune, 'X', tcell.ModAlt|tcell.ModShift), "Alt+X"},
+		{"alt+space", tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModAlt), "Alt+Space"},
+		{"pgdn", tcell.NewEventKey(tcell.KeyPgDn, 0, tcell.ModNone), "PgDn"},
+		{"tab", tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone), "Tab"},
+		{"enter", tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), "Enter"},
+	}
+
+	for _, tt := range tests {
+		binding, err := parseKey(tt.key)
+		if err != nil {
+			t.Errorf("parseKey(%q) returned error: %v", tt.key, err)
+			continue
+		}
+		if binding != eventBinding(tt.event) {
+			t.Errorf("Expected %q to match its key event, got %+v and %+v", tt.key, binding, eventBinding(tt.event))
+		}
+		if binding.String() != tt.expected {
+			t.Errorf("Expected %q to be shown as %q, got %q", tt.key, tt.expected, binding.String())
+		}
+	}
+}
+
+func TestParseKeyErrors(t *testing.T) {
+	for _, key := range []string{"ctrl+1", "hyper+q", "f99", ""} {
+		if _, err := parseKey(key); err == nil {
+			t.Errorf("Expected an error for %q", key)
+		}
+	}
+}
+
+func TestNewKeymapErrors(t *testing.T) {
+	tests := []struct {
+		keys     map[string][]string
+		expected string
+	}{
+		{map[string][]string{"explode": {"f10"}}, "keys.explode: unknown action"},
+		{map[string][]string{"quit": {"ctrl+1"}}, "keys.quit: invalid key"},
+		{map[string][]string{"quit": {"q"}}, "could no longer be typed"},
+		{map[string][]string{"quit": {"f1"}}, "F1 is already bound to help"},
+		{map[string][]string{"quit": {"tab"}}, "keys.quit: Tab is already bound to autocomplete"},
+		{map[string][]string{"load_schema": {"f9"}}, "keys.load_schema: F9 is already bound to stats"},
+		{map[string][]string{"autocomplete": {"x"}}, "could no longer be typed"},
+		{map[string][]string{"focus_input": {"ctrl+n"}}, "keys.focus_input: Ctrl+N is already bound to next_document"},
+	}
+
+	for _, tt := range tests {
+		err := CheckKeys(tt.keys, false)
+		if err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("Expected error containing %q for %v, got %v", tt.expected, tt.keys, err)
+		}
+	}
+}
+
+func TestAppWithConfig(t *testing.T) {
+	cfg := config.Default()
+	cfg.Keys = map[string][]string{"quit": {"ctrl+x"}, "copy": {}}
+	cfg.Theme["border_focused"] = "#ff8800"
+	cfg.Defaults.SaveFilename = "result.json"
+	cfg.Defaults.Language = "python"
+
+	app, err := NewAppWithConfig([]Document{{Name: "doc.json", JSONData: `{"a": 1}`}}, cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if act := app.keys.lookup(contextGlobal, tcell.NewEventKey(tcell.KeyCtrlX, 0, tcell.ModCtrl)); act == nil || act.name != "quit" {
+		t.Error("Expected Ctrl+X to quit")
+	}
+	if app.keys.lookup(contextGlobal, tcell.NewEventKey(tcell.KeyCtrlQ, 0, tcell.ModCtrl)) != nil {
+		t.Error("Expected Ctrl+Q to be unbound")
+	}
+	if !strings.Contains(app.originalFooterText, "Ctrl+X[-::-]: Quit") || strings.Contains(app.originalFooterText, "Copy") {
+		t.Errorf("Expected the footer to show the configured keys, got %q", app.originalFooterText)
+	}
+	if app.theme.BorderFocused != tcell.NewHexColor(0xff8800) {
+		t.Errorf("Expected the configured border color, got %v", app.theme.BorderFocused)
+	}
+	if app.saveFilename != "result.json" {
+		t.Errorf("Expected the configured save filename, got %q", app.saveFilename)
+	}
+
+	// The code preview starts with the default language and closes after the one before it
+	app.cycleCodeView()
+	for range 2 {
+		app.cycleCodeView()
+	}
+	if app.outputView != ViewCode || app.codeLanguage.String() != "TypeScript" {
+		t.Errorf("Expected the TypeScript preview after cycling from Python, got %v %v", app.outputView, app.codeLanguage)
+	}
+	app.cycleCodeView()
+	if app.outputView != ViewResult {
+		t.Errorf("Expected the preview to close, got %v", app.outputView)
+	}
+}
+
+func TestKeyContexts(t *testing.T) {
+	// The same key can be bound in contexts that never see it at the same time
+	keys := map[string][]string{"load_schema": {"n"}, "next_change": {"o"}}
+	km, err := newKeymap(keys, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	event := tcell.NewEventKey(tcell.KeyRune, 'n', tcell.ModNone)
+	if act := km.lookup(contextViolations, event); act == nil || act.name != "load_schema" {
+		t.Errorf("Expected n to load a schema in the violations list, got %v", act)
+	}
+	if act := km.lookup(contextOutput, event); act != nil {
+		t.Errorf("Expected n to be unbound in the output panel, got %s", act.name)
+	}
+	if act := km.lookup(contextInput, tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone)); act == nil || act.name != "hide_suggestions" {
+		t.Errorf("Expected Esc to hide the suggestions in the input field, got %v", act)
+	}
+	if act := km.lookup(contextHelp, tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone)); act == nil || act.name != "close_help" {
+		t.Errorf("Expected Esc to close the help panel, got %v", act)
+	}
+}
+
+func TestRemappedInputKeys(t *testing.T) {
+	cfg := config.Default()
+	cfg.Keys = map[string][]string{"autocomplete": {"ctrl+space"}, "help": {"tab"}}
+	if _, err := NewAppWithConfig([]Document{{Name: "doc.json", JSONData: `{"a": 1}`}}, cfg); err == nil {
+		t.Error("Expected an error for Tab bound globally and in the input field")
+	}
+
+	cfg.Keys = map[string][]string{"autocomplete": {"alt+a"}}
+	app, err := NewAppWithConfig([]Document{{Name: "doc.json", JSONData: `{"alpha": 1, "beta": 2}`}}, cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if app.dispatch(contextInput, tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone)) == nil {
+		t.Error("Expected Tab to reach the input field")
+	}
+	if app.dispatch(contextInput, tcell.NewEventKey(tcell.KeyRune, 'a', tcell.ModAlt)) != nil || !app.dropdownVisible {
+		t.Error("Expected Alt+A to show the suggestions")
+	}
+	if !strings.Contains(app.originalFooterText, "Alt+a[-::-]: Autocomplete") {
+		t.Errorf("Expected the footer to show the autocomplete key, got %q", app.originalFooterText)
+	}
+	if help := app.helpPanel.GetText(true); !strings.Contains(help, "Alt+a") || !strings.Contains(help, "Show suggestions for the path") {
+		t.Errorf("Expected the help panel to list the autocomplete key, got %q", help)
+	}
+}
+
+func TestCtrlCQuits(t *testing.T) {
+	km, err := newKeymap(nil, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if act := km.lookup(contextGlobal, tcell.NewEventKey(tcell.KeyCtrlC, 0, tcell.ModCtrl)); act == nil || act.name != "quit" {
+		t.Errorf("Expected Ctrl+C to quit, got %v", act)
+	}
+	if act := km.lookup(contextGlobal, tcell.NewEventKey(tcell.KeyRune, 'c', tcell.ModAlt)); act == nil || act.name != "copy" {
+		t.Errorf("Expected Alt+C to copy, got %v", act)
+	}
+}
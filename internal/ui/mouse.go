@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gataky/dive/internal/query"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/tidwall/gjson"
+)
+
+// Limits of the share of the width taken by the main pane when dragging the divider
+const (
+	minSplitRatio = 10
+	maxSplitRatio = 90
+)
+
+// setupMouse makes keys in the output, footer hints and the split divider
+// respond to the mouse. Suggestions are selected and output panels scroll
+// through tview's own mouse handling.
+func (a *App) setupMouse() {
+	a.outputPanel.SetHighlightedFunc(a.outputClicked)
+	a.footer.SetRegions(true).
+		SetHighlightedFunc(a.footerClicked)
+	a.tviewApp.SetMouseCapture(a.dragDivider)
+}
+
+// renderResult shows a result with every key, and every element of an array,
+// marked as a region that can be clicked to set the path to it
+func (a *App) renderResult(value string) {
+	// Objects and arrays are outlined as shown, pretty-printed, and other
+	// values by their type, since a string may hold text that looks like JSON
+	shown := gjson.Parse(a.currentDocument().queryEngine.GetLastValidRaw())
+	if shown.IsObject() || shown.IsArray() {
+		shown = gjson.Parse(value)
+	}
+	text, paths := query.Outline(shown, a.collapsed)
+	a.linePaths = paths
+
+	a.outputLines = a.outputLines[:0]
+	for i, line := range strings.Split(text, "\n") {
+		a.outputLines = append(a.outputLines, clickableLine(line, paths[i], fmt.Sprintf("L%d", i)))
+	}
+	a.cursor = min(a.cursor, len(a.outputLines)-1)
+	a.drawCursor(a.outputPanel.HasFocus())
+}
+
+// clickableLine escapes a line of pretty-printed JSON and marks its key, or
+// the element of an array it starts with, as the region id
+func clickableLine(line, path, id string) string {
+	content := strings.TrimLeft(line, " ")
+	indent := line[:len(line)-len(content)]
+	if path == "" || content == "" || strings.ContainsAny(content[:1], "}]") {
+		return tview.Escape(line)
+	}
+
+	end := len(strings.TrimSuffix(content, ","))
+	if key := jsonStringLength(content); key > 0 && strings.HasPrefix(content[key:], ":") {
+		end = key
+	}
+	return fmt.Sprintf(`%s["%s"]%s[""]%s`, indent, id, tview.Escape(content[:end]), tview.Escape(content[end:]))
+}
+
+// jsonStringLength returns the length of the JSON string s starts with, or 0
+func jsonStringLength(s string) int {
+	if !strings.HasPrefix(s, `"`) {
+		return 0
+	}
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// outputClicked sets the path to the key clicked in the result, or selects
+// the change clicked in the diff view
+func (a *App) outputClicked(added, removed, remaining []string) {
+	if len(added) == 0 {
+		return
+	}
+
+	if a.diffMode {
+		if index, err := strconv.Atoi(added[0]); err == nil && index != a.diffSelected {
+			a.selectChange(index)
+		}
+		return
+	}
+
+	a.outputPanel.Highlight()
+	line, err := strconv.Atoi(strings.TrimPrefix(added[0], "L"))
+	if err != nil || line >= len(a.linePaths) {
+		return
+	}
+	a.navigate(query.JoinPath(a.currentDocument().queryEngine.GetLastValidPath(), a.linePaths[line]))
+}
+
+// footerClicked runs the action of the footer hint that was clicked
+func (a *App) footerClicked(added, removed, remaining []string) {
+	a.footer.Highlight()
+	if len(added) == 0 {
+		return
+	}
+	if act := a.keys.find(added[0]); act != nil {
+		act.run(a)
+	}
+}
+
+// dragDivider resizes the panes when the border between the main and the
+// split pane is dragged
+func (a *App) dragDivider(event *tcell.EventMouse, action tview.MouseAction) (*tcell.EventMouse, tview.MouseAction) {
+	if !a.split.visible {
+		a.split.dragging = false
+		return event, action
+	}
+
+	x, y := event.Position()
+	switch action {
+	case tview.MouseLeftDown:
+		left, top, width, height := a.split.mainPane.GetRect()
+		if (x == left+width-1 || x == left+width) && y >= top && y < top+height {
+			a.split.dragging = true
+			return nil, action
+		}
+	case tview.MouseMove:
+		if a.split.dragging {
+			a.resizeSplit(x)
+			return nil, action
+		}
+	case tview.MouseLeftUp:
+		if a.split.dragging {
+			a.split.dragging = false
+			return nil, action
+		}
+	}
+	return event, action
+}
+
+// resizeSplit moves the divider between the main and the split pane to column x
+func (a *App) resizeSplit(x int) {
+	left, _, width, _ := a.split.panes.GetRect()
+	if a.stats.visible {
+		// The statistics panel keeps its width
+		width -= statsPanelWidth
+	}
+	if width <= 0 {
+		return
+	}
+
+	ratio := (x - left + 1) * 100 / width
+	a.split.ratio = min(max(ratio, minSplitRatio), maxSplitRatio)
+	a.rebuildLayout()
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestClickableLine(t *testing.T) {
+	tests := []struct {
+		line     string
+		path     string
+		expected string
+	}{
+		{`  "name": "Alice",`, "name", `  ["L1"]"name"[""]: "Alice",`},
+		{`  "a\"b": [1],`, `a"b`, `  ["L1"]"a\"b"[""]: [1[],`},
+		{`    2,`, "1", `    ["L1"]2[""],`},
+		{`  {`, "0", `  ["L1"]{[""]`},
+		{`  ["x"]`, "0", `  ["L1"]["x"[][""]`},
+		{`  },`, "0", `  },`},
+		{`{`, "", `{`},
+	}
+	for _, tt := range tests {
+		if got := clickableLine(tt.line, tt.path, "L1"); got != tt.expected {
+			t.Errorf("clickableLine(%q) = %q, expected %q", tt.line, got, tt.expected)
+		}
+	}
+}
+
+func TestRenderScalarResults(t *testing.T) {
+	app := NewApp([]Document{{Name: "doc.json", JSONData: `{"body": "{\n\n  \"a\": 1\n}", "n": 7}`}})
+
+	// A string holding JSON is shown as text, one line of the result per line
+	app.inputField.SetText("body")
+	if len(app.linePaths) != 4 || len(app.outputLines) != 4 {
+		t.Errorf("Expected 4 lines with a path each, got %d lines and %q", len(app.outputLines), app.linePaths)
+	}
+	if text := app.outputPanel.GetText(true); !strings.Contains(text, `"a": 1`) {
+		t.Errorf("Expected the text of the string, got %q", text)
+	}
+
+	app.inputField.SetText("n")
+	if text := app.outputPanel.GetText(true); text != "7" || len(app.linePaths) != 1 {
+		t.Errorf("Expected the number alone, got %q and %q", text, app.linePaths)
+	}
+}
+
+func TestMouseClicks(t *testing.T) {
+	app := NewApp([]Document{{Name: "doc.json", JSONData: `{"users": [{"name": "Alice"}, {"name": "Bob"}]}`}})
+	app.inputField.SetText("users")
+
+	// Clicking the key on a line sets the path to it
+	for i, path := range app.linePaths {
+		if path == "1" {
+			app.outputPanel.Highlight(fmt.Sprintf("L%d", i))
+			break
+		}
+	}
+	if got := app.inputField.GetText(); got != "users.1" {
+		t.Errorf("Expected the clicked element to set the path, got %q", got)
+	}
+
+	// Clicking a footer hint runs its action
+	app.footer.Highlight("help")
+	if !app.helpPanelVisible {
+		t.Error("Expected clicking the help hint to show help")
+	}
+}
+
+func TestResizeSplit(t *testing.T) {
+	app := NewApp([]Document{{Name: "doc.json", JSONData: `{"a": 1}`}})
+	app.toggleSplitPane()
+	app.split.panes.SetRect(0, 0, 100, 20)
+
+	app.resizeSplit(29)
+	if app.split.ratio != 30 {
+		t.Errorf("Expected a ratio of 30, got %d", app.split.ratio)
+	}
+	app.resizeSplit(99)
+	if app.split.ratio != maxSplitRatio {
+		t.Errorf("Expected the ratio to be clamped to %d, got %d", maxSplitRatio, app.split.ratio)
+	}
+}
@@ -0,0 +1,238 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gataky/dive/internal/codegen"
+	"github.com/gataky/dive/internal/schema"
+	"github.com/gataky/dive/internal/ui/theme"
+	"github.com/rivo/tview"
+)
+
+// Size of the command palette
+const (
+	paletteWidth  = 76
+	paletteHeight = 20
+)
+
+// commandPalette holds the components of the command palette
+type commandPalette struct {
+	visible bool
+	view    *tview.Flex
+	input   *tview.InputField
+	list    *tview.List
+	entries []paletteEntry  // Entries matching the filter, in list order
+	focus   tview.Primitive // Component focused before the palette opened
+}
+
+// paletteEntry is a command offered by the command palette
+type paletteEntry struct {
+	title string
+	keys  string // Keys bound to the command, if any
+	run   func()
+}
+
+// initPalette creates the components of the command palette
+func (a *App) initPalette() {
+	a.palette.input = createInputField(a.theme).
+		SetLabel("> ").
+		SetPlaceholder("Type to filter commands")
+	a.palette.input.SetBorder(false)
+	a.palette.input.SetChangedFunc(a.filterPalette)
+	a.palette.input.SetInputCapture(a.captureKeys(contextPalette))
+
+	a.palette.list = createAutocompleteDropdown(a.theme)
+	a.palette.list.SetBorder(false)
+
+	a.palette.view = tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(a.palette.input, 1, 0, true).
+		AddItem(a.palette.list, 0, 1, false)
+	a.palette.view.SetBorder(true).
+		SetTitle(" Commands ")
+	styleBox(a.palette.view.Box, a.theme)
+}
+
+// togglePalette opens the command palette, or closes it if it is open
+func (a *App) togglePalette() {
+	if a.palette.visible {
+		a.hidePalette()
+		return
+	}
+
+	a.palette.visible = true
+	a.palette.focus = a.tviewApp.GetFocus()
+	a.palette.input.SetText("")
+	a.filterPalette("")
+
+	// The palette floats above the layout
+	pages := tview.NewPages().
+		AddPage("main", a.layout, true, true).
+		AddPage("palette", centered(a.palette.view, paletteWidth, paletteHeight), true, true)
+	a.tviewApp.SetRoot(pages, true)
+	a.tviewApp.SetFocus(a.palette.input)
+}
+
+// hidePalette closes the command palette and focuses the component that had focus before
+func (a *App) hidePalette() {
+	a.palette.visible = false
+	a.restoreLayout(a.palette.focus)
+}
+
+// runPaletteEntry closes the command palette and runs the selected command
+func (a *App) runPaletteEntry() {
+	index := a.palette.list.GetCurrentItem()
+	if index < 0 || index >= len(a.palette.entries) {
+		return
+	}
+	entry := a.palette.entries[index]
+	a.hidePalette()
+	entry.run()
+}
+
+// movePaletteSelection selects the entry offset items away, wrapping around at either end
+func (a *App) movePaletteSelection(offset int) {
+	count := a.palette.list.GetItemCount()
+	if count > 0 {
+		current := a.palette.list.GetCurrentItem()
+		a.palette.list.SetCurrentItem(((current+offset)%count + count) % count)
+	}
+}
+
+// filterPalette lists the commands matching pattern, best matches first
+func (a *App) filterPalette(pattern string) {
+	type match struct {
+		entry paletteEntry
+		score int
+	}
+	var matches []match
+	for _, entry := range a.paletteEntries() {
+		if score, ok := fuzzyScore(pattern, entry.title); ok {
+			matches = append(matches, match{entry, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	a.palette.list.Clear()
+	a.palette.entries = a.palette.entries[:0]
+	for _, m := range matches {
+		text := fmt.Sprintf("%-52s [%s]%s[-]", tview.Escape(m.entry.title), a.theme.TextMuted, tview.Escape(m.entry.keys))
+		run := m.entry.run
+		a.palette.list.AddItem(text, "", 0, func() {
+			// Clicked with the mouse
+			a.hidePalette()
+			run()
+		})
+		a.palette.entries = append(a.palette.entries, m.entry)
+	}
+}
+
+// paletteEntries returns every command available in the current mode: the
+// actions with their keys, then themes, code preview languages and export formats
+func (a *App) paletteEntries() []paletteEntry {
+	var entries []paletteEntry
+	for _, act := range a.keys.actions {
+		if !act.palette && !act.activeIn(contextGlobal) || act.name == "command_palette" ||
+			act.available != nil && !act.available(a) {
+			continue
+		}
+		run := act.run
+		entries = append(entries, paletteEntry{
+			title: act.description,
+			keys:  a.keys.label(act.name),
+			run:   func() { run(a) },
+		})
+	}
+
+	for _, name := range theme.Names {
+		entries = append(entries, paletteEntry{
+			title: "Theme: " + name,
+			run:   func() { a.setTheme(name) },
+		})
+	}
+	for _, lang := range codegen.Languages {
+		entries = append(entries, paletteEntry{
+			title: "Code preview: " + lang.String(),
+			run:   func() { a.showCodePreview(lang) },
+		})
+	}
+
+	entries = append(entries, paletteEntry{
+		title: "Export as JSON Schema",
+		run: func() {
+			a.showSaveDialogFor(" Export JSON Schema ", "schema.json", func() string {
+				return schema.Infer(a.currentDocument().queryEngine.GetLastValidRaw()).JSONSchema()
+			})
+		},
+	})
+	for _, lang := range codegen.Languages {
+		entries = append(entries, paletteEntry{
+			title: fmt.Sprintf("Export as %s types", lang),
+			run: func() {
+				a.showSaveDialogFor(" Export "+lang.String()+" Types ", "types"+lang.FileExtension(), func() string {
+					node := schema.Infer(a.currentDocument().queryEngine.GetLastValidRaw())
+					return codegen.Generate(lang, node, codeRootName(a.inputField.GetText()))
+				})
+			},
+		})
+	}
+	return entries
+}
+
+// showCodePreview shows the code preview in a language
+func (a *App) showCodePreview(lang codegen.Language) {
+	if a.diffMode || a.split.visible || a.compareMode != CompareOff {
+		a.showMessage("Code preview is only available for a single result", true)
+		return
+	}
+	a.codeLanguage = lang
+	a.setOutputView(ViewCode)
+}
+
+// fuzzyScore reports whether the characters of pattern appear in text in
+// order, ignoring case and spaces, and how well they match: characters at
+// the start of words and runs of consecutive characters score higher
+func fuzzyScore(pattern, text string) (int, bool) {
+	want := []rune(strings.ToLower(strings.ReplaceAll(pattern, " ", "")))
+	if len(want) == 0 {
+		return 0, true
+	}
+
+	score, next, previous := 0, 0, -2
+	runes := []rune(strings.ToLower(text))
+	for i, r := range runes {
+		if next == len(want) {
+			break
+		}
+		if r != want[next] {
+			continue
+		}
+		score++
+		if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+			score += 5
+		}
+		if previous == i-1 {
+			score += 3
+		}
+		previous = i
+		next++
+	}
+	return score, next == len(want)
+}
+
+// centered places a primitive of the given size in the middle of the screen
+func centered(p tview.Primitive, width, height int) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().
+			SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 0, true).
+			AddItem(nil, 0, 1, false), width, 0, true).
+		AddItem(nil, 0, 1, false)
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gataky/dive/internal/codegen"
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		pattern string
+		text    string
+		matches bool
+	}{
+		{"", "Anything", true},
+		{"copy", "Copy the output to the clipboard", true},
+		{"thm lig", "Theme: light", true},
+		{"CLIP", "Copy the output to the clipboard", true},
+		{"xyz", "Theme: light", false},
+		{"lt", "Theme: dark", false},
+	}
+	for _, tt := range tests {
+		if _, ok := fuzzyScore(tt.pattern, tt.text); ok != tt.matches {
+			t.Errorf("fuzzyScore(%q, %q) = %t, expected %t", tt.pattern, tt.text, ok, tt.matches)
+		}
+	}
+
+	// Matches at the start of words rank higher than matches inside them
+	start, _ := fuzzyScore("sp", "Show or hide the split pane")
+	inside, _ := fuzzyScore("sp", "Display everything")
+	if start <= inside {
+		t.Errorf("Expected word starts to score higher, got %d and %d", start, inside)
+	}
+}
+
+func TestCommandPalette(t *testing.T) {
+	app := NewApp([]Document{{Name: "doc.json", JSONData: `{"users": [{"name": "Alice"}]}`}})
+	app.tviewApp.SetFocus(app.outputPanel)
+
+	app.dispatch(contextGlobal, tcell.NewEventKey(tcell.KeyCtrlP, 0, tcell.ModCtrl))
+	if !app.palette.visible || app.tviewApp.GetFocus() != app.palette.input {
+		t.Fatal("Expected Ctrl+P to open the command palette")
+	}
+
+	// Every global action is listed with its keys
+	found := false
+	for _, entry := range app.palette.entries {
+		found = found || entry.title == "Show or hide the inferred schema" && entry.keys == "F6"
+	}
+	if !found {
+		t.Error("Expected the schema view to be listed with its key")
+	}
+
+	app.palette.input.SetText("theme light")
+	if len(app.palette.entries) == 0 || app.palette.entries[0].title != "Theme: light" {
+		t.Fatalf("Expected the light theme to match best, got %+v", app.palette.entries)
+	}
+	app.dispatch(contextPalette, tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	if app.palette.visible || app.themeName != "light" {
+		t.Errorf("Expected the palette to close and switch themes, got theme %q", app.themeName)
+	}
+	if app.tviewApp.GetFocus() != app.outputPanel {
+		t.Error("Expected focus to return to the output panel")
+	}
+
+	app.togglePalette()
+	app.palette.input.SetText("code preview python")
+	app.dispatch(contextPalette, tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	if app.outputView != ViewCode || app.codeLanguage != codegen.Python {
+		t.Errorf("Expected the Python code preview, got %s in %s", app.outputView, app.codeLanguage)
+	}
+
+	app.togglePalette()
+	app.dispatch(contextPalette, tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	if app.palette.visible {
+		t.Error("Expected Esc to close the palette")
+	}
+	if !strings.Contains(app.originalFooterText, "Ctrl+P[-::-]: Commands") {
+		t.Errorf("Expected the footer to show the palette key, got %q", app.originalFooterText)
+	}
+}
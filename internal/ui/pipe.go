@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	"github.com/tidwall/gjson"
+)
+
+// pipePane holds the scratch pane showing the output of a shell command the
+// current result was piped through
+type pipePane struct {
+	visible    bool
+	view       *tview.TextView
+	command    string             // Last command run
+	output     string             // Standard output of the last command that finished
+	cancel     context.CancelFunc // Cancels the command in progress
+	generation int                // Incremented per command so output of stopped ones is dropped
+}
+
+// initPipePane creates the scratch pane
+func (a *App) initPipePane() {
+	a.pipe.view = a.newOutputPanel()
+	a.pipe.view.SetInputCapture(a.captureKeys(contextPipe))
+	a.pipe.view.SetFocusFunc(func() {
+		a.setComponentFocus(FocusPipe)
+	})
+}
+
+// promptPipe asks for a shell command to pipe the current result through
+func (a *App) promptPipe() {
+	a.showPrompt(" Pipe Through Command ", "| ", a.pipe.command, a.startPipe)
+}
+
+// startPipe runs command with the current result on stdin in the background,
+// stopping any command still running, and shows its output in the scratch pane
+func (a *App) startPipe(command string) {
+	a.stopPipe()
+	ctx, cancel := context.WithTimeout(context.Background(), a.pipeTimeout)
+	a.pipe.cancel = cancel
+	generation := a.pipe.generation
+	a.pipe.command, a.pipe.output = command, ""
+
+	a.pipe.view.SetTitle(fmt.Sprintf(" | %s (running, %s to cancel) ", tview.Escape(command), a.keys.label("close_pipe")))
+	a.pipe.view.SetText("")
+	if !a.pipe.visible {
+		a.pipe.visible = true
+		a.rebuildLayout()
+	}
+	a.tviewApp.SetFocus(a.pipe.view)
+
+	input, timeout := a.activeEngine().GetLastValidValue(), a.pipeTimeout
+	go func() {
+		output, stderr, err := runPipe(ctx, command, input)
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("timed out after %s", timeout)
+		}
+		a.tviewApp.QueueUpdateDraw(func() {
+			a.showPipeOutput(generation, output, stderr, err)
+		})
+	}()
+}
+
+// runPipe runs command with the shell, writing input to its stdin, and
+// returns what it wrote to stdout and stderr
+func runPipe(ctx context.Context, command, input string) (string, string, error) {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = strings.NewReader(input)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Don't wait for children that keep the output pipes open after a cancellation
+	cmd.WaitDelay = 100 * time.Millisecond
+
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
+// showPipeOutput shows the output of the given command unless it was stopped.
+// Errors and anything written to stderr are shown in the footer.
+func (a *App) showPipeOutput(generation int, output, stderr string, err error) {
+	if generation != a.pipe.generation {
+		return
+	}
+	if a.pipe.cancel != nil {
+		a.pipe.cancel()
+		a.pipe.cancel = nil
+	}
+
+	a.pipe.output = output
+	a.pipe.view.SetText(tview.Escape(output))
+	a.pipe.view.ScrollToBeginning()
+
+	title := " | " + tview.Escape(a.pipe.command) + " "
+	// The footer has a single line
+	stderr = strings.Join(strings.Fields(stderr), " ")
+	switch {
+	case err != nil && stderr != "":
+		a.showMessage(tview.Escape(fmt.Sprintf("Error: %v: %s", err, stderr)), true)
+		title += "(failed) "
+	case err != nil:
+		a.showMessage(tview.Escape(fmt.Sprintf("Error: %v", err)), true)
+		title += "(failed) "
+	case stderr != "":
+		a.showMessage(tview.Escape(stderr), true)
+	case gjson.Valid(output):
+		a.showMessage(fmt.Sprintf("Press %s to open the output as a document", a.keys.label("open_pipe_output")), false)
+	}
+	a.pipe.view.SetTitle(title)
+}
+
+// stopPipe cancels the command in progress, if any, and drops its output
+func (a *App) stopPipe() {
+	if a.pipe.cancel != nil {
+		a.pipe.cancel()
+		a.pipe.cancel = nil
+	}
+	a.pipe.generation++
+}
+
+// closePipe stops the command in progress and hides the scratch pane
+func (a *App) closePipe() {
+	a.stopPipe()
+	a.pipe.visible = false
+	a.rebuildLayout()
+	a.focusOutput()
+}
+
+// openPipeOutput opens the output of the last command as a new document
+func (a *App) openPipeOutput() {
+	switch {
+	case a.pipe.cancel != nil:
+		a.showMessage("The command is still running", true)
+		return
+	case !gjson.Valid(a.pipe.output):
+		a.showMessage("The output is not JSON", true)
+		return
+	}
+
+	a.openDocument(Document{Name: "| " + a.pipe.command, JSONData: a.pipe.output})
+	a.closePipe()
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestRunPipe(t *testing.T) {
+	output, _, err := runPipe(context.Background(), "tr a-z A-Z", "abc")
+	if err != nil || output != "ABC" {
+		t.Errorf("Expected ABC, got %q and %v", output, err)
+	}
+
+	_, stderr, err := runPipe(context.Background(), "echo oops >&2; exit 3", "")
+	if err == nil || strings.TrimSpace(stderr) != "oops" {
+		t.Errorf("Expected the command to fail with oops on stderr, got %q and %v", stderr, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, _, err := runPipe(ctx, "sleep 5", ""); err == nil || time.Since(start) > 2*time.Second {
+		t.Errorf("Expected the command to be stopped at the timeout, got %v after %s", err, time.Since(start))
+	}
+}
+
+func TestPipePane(t *testing.T) {
+	app := NewApp([]Document{{Name: "doc.json", JSONData: `{"users": [{"name": "Alice"}, {"name": "Bob"}]}`}})
+	app.inputField.SetText("users.#.name")
+
+	app.startPipe("sort -r")
+	if !app.pipe.visible || app.tviewApp.GetFocus() != app.pipe.view || app.pipe.cancel == nil {
+		t.Fatal("Expected the scratch pane to show the running command")
+	}
+
+	// Output of a command that was replaced is dropped
+	generation := app.pipe.generation
+	app.showPipeOutput(generation-1, "stale", "", nil)
+	if app.pipe.view.GetText(true) != "" {
+		t.Errorf("Expected stale output to be dropped, got %q", app.pipe.view.GetText(true))
+	}
+	app.showPipeOutput(generation, `["Bob", "Alice"]`, "", nil)
+	if app.pipe.view.GetText(true) != `["Bob", "Alice"]` || app.pipe.cancel != nil {
+		t.Errorf("Expected the output of the command, got %q", app.pipe.view.GetText(true))
+	}
+
+	app.dispatch(contextPipe, tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+	if len(app.documents) != 2 || app.activeDocument != 1 || app.currentDocument().name != "| sort -r" {
+		t.Fatalf("Expected Enter to open the output as a document, got %d documents", len(app.documents))
+	}
+	if app.pipe.visible {
+		t.Error("Expected the scratch pane to close")
+	}
+	if app.inputField.SetText("0"); app.currentDocument().queryEngine.GetLastValidValue() != "Bob" {
+		t.Errorf("Expected to query the new document, got %q", app.currentDocument().queryEngine.GetLastValidValue())
+	}
+}
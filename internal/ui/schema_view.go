@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"github.com/gataky/dive/internal/schema"
+	"github.com/rivo/tview"
+)
+
+// toggleSchemaView switches the output panel between the result and its inferred schema
+func (a *App) toggleSchemaView() {
+	if a.diffMode || a.split.visible || a.compareMode != CompareOff {
+		a.showMessage("Schema view is only available for a single result", true)
+		return
+	}
+
+	if a.outputView == ViewSchema {
+		a.setOutputView(ViewResult)
+	} else {
+		a.setOutputView(ViewSchema)
+	}
+}
+
+// renderSchema infers the shape of the current result and shows it as a tree
+func (a *App) renderSchema() {
+	// Invalid paths keep showing the schema of the last valid result
+	a.schemaNode = schema.Infer(a.currentDocument().queryEngine.GetLastValidRaw())
+	a.outputPanel.SetText(tview.Escape(a.schemaNode.Tree()))
+}
+
+// schemaJSON returns the inferred schema as a JSON Schema document
+func (a *App) schemaJSON() string {
+	if a.schemaNode == nil {
+		return ""
+	}
+	return a.schemaNode.JSONSchema()
+}
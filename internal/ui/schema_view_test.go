@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSchemaView(t *testing.T) {
+	app := NewApp([]Document{{Name: "users.json", JSONData: `{"users": [{"id": 1, "name": "A"}, {"id": 2}]}`}})
+
+	app.inputField.SetText("users")
+	app.toggleSchemaView()
+
+	tree := app.outputPanel.GetText(true)
+	if !strings.HasPrefix(tree, "array<object> [2 items]") {
+		t.Errorf("Expected schema tree of the users array, got:\n%s", tree)
+	}
+	if !strings.Contains(tree, "name?: string") {
+		t.Errorf("Expected optional name property, got:\n%s", tree)
+	}
+
+	// Exporting from the schema view produces a JSON Schema
+	if !strings.Contains(app.outputText(), `"$schema": "https://json-schema.org/draft/2020-12/schema"`) {
+		t.Errorf("Expected JSON Schema export, got:\n%s", app.outputText())
+	}
+
+	// Invalid paths keep the schema of the last valid result
+	app.inputField.SetText("users.missing")
+	if !strings.HasPrefix(app.outputPanel.GetText(true), "array<object>") {
+		t.Errorf("Expected schema of last valid result, got:\n%s", app.outputPanel.GetText(true))
+	}
+
+	app.toggleSchemaView()
+	if !strings.Contains(app.outputPanel.GetText(true), `"id": 1`) {
+		t.Errorf("Expected plain result after closing schema view, got:\n%s", app.outputPanel.GetText(true))
+	}
+}
@@ -0,0 +1,195 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gataky/dive/internal/diff"
+	"github.com/gataky/dive/internal/query"
+	"github.com/rivo/tview"
+)
+
+// splitPane holds the second input field/output panel pair shown next to the
+// main one, used to compare two results of the same document
+type splitPane struct {
+	visible              bool
+	input                *tview.InputField
+	output               *tview.TextView
+	engine               *query.Engine // Separate engine over the same data so last valid state is independent
+	active               bool          // Whether the split pane, rather than the main pane, was used last
+	leftValue            string        // Plain result shown in the main output panel
+	rightValue           string        // Plain result shown in the split output panel
+	syncScroll           bool
+	highlightDifferences bool
+	lastLeftRow          int // Scroll offsets seen at the previous synchronization
+	lastLeftCol          int
+	lastRightRow         int
+	lastRightCol         int
+	ratio                int         // Percentage of the width taken by the main pane
+	panes                *tview.Flex // Row of panes, and the main pane in it, for dragging the divider
+	mainPane             *tview.Flex
+	dragging             bool // Whether the divider is being dragged
+	pending              bool // Whether the result waits for a plugin running in the background
+}
+
+// initSplitPane creates the components of the split pane
+func (a *App) initSplitPane() {
+	a.split.input = createInputField(a.theme)
+	a.split.output = a.newOutputPanel()
+	a.split.ratio = 50
+
+	a.split.input.SetChangedFunc(a.querySplit)
+
+	a.split.input.SetFocusFunc(func() {
+		a.split.active = true
+		a.setComponentFocus(FocusSplitInput)
+	})
+	a.split.output.SetFocusFunc(func() {
+		a.split.active = true
+		a.setComponentFocus(FocusSplitOutput)
+	})
+}
+
+// toggleSplitPane shows or hides the second input field/output panel pair
+func (a *App) toggleSplitPane() {
+	if a.diffMode || a.compareMode != CompareOff {
+		a.showMessage("Split view is not available while comparing documents", true)
+		return
+	}
+	if a.outputView != ViewResult {
+		a.showMessage("Close the "+a.outputView.String()+" first", true)
+		return
+	}
+
+	a.hideDropdown()
+	a.split.visible = !a.split.visible
+
+	if a.split.visible {
+		doc := a.currentDocument()
+		a.split.engine = a.newSplitEngine(doc.jsonData)
+		a.split.leftValue = doc.queryEngine.Query(a.inputField.GetText()).Value
+		a.split.rightValue = a.split.engine.Query(a.split.input.GetText()).Value
+		a.rebuildLayout()
+		a.renderSplit()
+		a.tviewApp.SetFocus(a.split.input)
+	} else {
+		a.split.active = false
+		a.rebuildLayout()
+		// Restore the plain result without difference highlighting
+		a.runQuery(a.inputField.GetText())
+		a.tviewApp.SetFocus(a.inputField)
+	}
+}
+
+// toggleSyncScroll turns synchronized scrolling of the two output panels on or off
+func (a *App) toggleSyncScroll() {
+	if !a.split.visible {
+		return
+	}
+
+	a.split.syncScroll = !a.split.syncScroll
+	if a.split.syncScroll {
+		// Align the split pane with the main pane
+		row, col := a.outputPanel.GetScrollOffset()
+		a.split.output.ScrollTo(row, col)
+		a.showMessage("Synchronized scrolling on", false)
+	} else {
+		a.showMessage("Synchronized scrolling off", false)
+	}
+}
+
+// toggleHighlightDifferences turns highlighting of differing lines on or off
+func (a *App) toggleHighlightDifferences() {
+	if !a.split.visible {
+		return
+	}
+
+	a.split.highlightDifferences = !a.split.highlightDifferences
+	a.renderSplit()
+}
+
+// switchPane moves focus between the main and the split input field
+func (a *App) switchPane() {
+	if !a.split.visible {
+		return
+	}
+
+	a.hideDropdown()
+	if a.split.active {
+		a.split.active = false
+		a.tviewApp.SetFocus(a.inputField)
+	} else {
+		a.tviewApp.SetFocus(a.split.input)
+	}
+}
+
+// renderSplit displays both results, highlighting differing lines if enabled
+func (a *App) renderSplit() {
+	if !a.split.visible {
+		return
+	}
+
+	if !a.split.highlightDifferences {
+		a.outputPanel.SetText(tview.Escape(a.split.leftValue))
+		a.split.output.SetText(tview.Escape(a.split.rightValue))
+		return
+	}
+
+	left := strings.Split(a.split.leftValue, "\n")
+	right := strings.Split(a.split.rightValue, "\n")
+	leftChanged, rightChanged := diff.LineChanges(left, right)
+
+	a.outputPanel.SetText(a.highlightLines(left, leftChanged))
+	a.split.output.SetText(a.highlightLines(right, rightChanged))
+}
+
+// highlightLines escapes lines and colors the ones marked as changed
+func (a *App) highlightLines(lines []string, changed []bool) string {
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		if changed[i] {
+			fmt.Fprintf(&b, "[%s]%s[-]", a.theme.TextAccent, tview.Escape(line))
+		} else {
+			b.WriteString(tview.Escape(line))
+		}
+	}
+	return b.String()
+}
+
+// syncSplitScroll mirrors the scroll position of whichever output panel moved
+func (a *App) syncSplitScroll() {
+	if !a.split.visible || !a.split.syncScroll {
+		return
+	}
+
+	leftRow, leftCol := a.outputPanel.GetScrollOffset()
+	rightRow, rightCol := a.split.output.GetScrollOffset()
+
+	if leftRow != a.split.lastLeftRow || leftCol != a.split.lastLeftCol {
+		a.split.output.ScrollTo(leftRow, leftCol)
+		rightRow, rightCol = leftRow, leftCol
+	} else if rightRow != a.split.lastRightRow || rightCol != a.split.lastRightCol {
+		a.outputPanel.ScrollTo(rightRow, rightCol)
+		leftRow, leftCol = rightRow, rightCol
+	}
+
+	a.split.lastLeftRow, a.split.lastLeftCol = leftRow, leftCol
+	a.split.lastRightRow, a.split.lastRightCol = rightRow, rightCol
+}
+
+// querySplit runs path from the split pane's input field, showing whether it is valid
+func (a *App) querySplit(path string) {
+	result := a.split.engine.Query(path)
+	a.split.pending = result.Pending
+	a.split.rightValue = result.Value
+	a.renderSplit()
+
+	if result.Pending {
+		a.showMessage(tview.Escape(result.Error), false)
+		return
+	}
+	a.showValidity(a.split.input, result.IsValid)
+}
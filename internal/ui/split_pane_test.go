@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitPaneIndependentQueries(t *testing.T) {
+	app := NewApp([]Document{{Name: "envs.json", JSONData: `{"envs": {"staging": {"port": 80}, "prod": {"port": 443}}}`}})
+
+	app.inputField.SetText("envs.staging.port")
+	app.toggleSplitPane()
+	if !app.split.visible {
+		t.Fatal("Expected split pane to be visible")
+	}
+
+	app.split.input.SetText("envs.prod.port")
+	if got := app.outputText(); got != "443" {
+		t.Errorf("Expected split pane result '443', got %q", got)
+	}
+
+	app.split.active = false
+	if got := app.outputText(); got != "80" {
+		t.Errorf("Expected main pane result '80', got %q", got)
+	}
+
+	// An invalid path in the split pane must not change the main engine's state
+	app.split.input.SetText("envs.missing")
+	if app.currentDocument().queryEngine.GetLastValidPath() != "envs.staging.port" {
+		t.Errorf("Expected main engine state to be untouched, got %q", app.currentDocument().queryEngine.GetLastValidPath())
+	}
+}
+
+func TestSplitPaneHighlightDifferences(t *testing.T) {
+	app := NewApp([]Document{{Name: "envs.json", JSONData: `{"staging": {"host": "s", "port": 80}, "prod": {"host": "p", "port": 80}}`}})
+
+	app.inputField.SetText("staging")
+	app.toggleSplitPane()
+	app.split.input.SetText("prod")
+	app.toggleHighlightDifferences()
+
+	left := app.outputPanel.GetText(false)
+	if !strings.Contains(left, `[`+app.theme.TextAccent.String()+`]  "host": "s",[-]`) {
+		t.Errorf("Expected differing host line to be highlighted, got:\n%s", left)
+	}
+	if strings.Contains(left, `[`+app.theme.TextAccent.String()+`]  "port": 80[-]`) {
+		t.Errorf("Expected equal port line not to be highlighted, got:\n%s", left)
+	}
+
+	// Copying must not include highlight markup
+	app.split.active = false
+	if strings.Contains(app.outputText(), "[-]") {
+		t.Errorf("Expected plain output text, got:\n%s", app.outputText())
+	}
+
+	app.toggleSplitPane()
+	if app.outputPanel.GetText(true) != app.split.leftValue {
+		t.Error("Expected plain result after closing the split pane")
+	}
+}
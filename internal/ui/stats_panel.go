@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gataky/dive/internal/query"
+	"github.com/gataky/dive/internal/stats"
+	"github.com/rivo/tview"
+)
+
+// statsPanelWidth is the width of the statistics panel in columns
+const statsPanelWidth = 48
+
+// statsPanel holds the statistics panel shown to the right of the output
+type statsPanel struct {
+	visible    bool
+	view       *tview.TextView
+	cancel     context.CancelFunc // Cancels the computation in progress
+	generation int                // Incremented per computation so stale results are dropped
+}
+
+// toggleStatsPanel shows or hides statistics of the current result
+func (a *App) toggleStatsPanel() {
+	a.stats.visible = !a.stats.visible
+	a.rebuildLayout()
+
+	if a.stats.visible {
+		a.refreshStats(a.currentDocument().queryEngine.Query(a.inputField.GetText()))
+	} else if a.stats.cancel != nil {
+		a.stats.cancel()
+		a.stats.cancel = nil
+	}
+}
+
+// refreshStats computes statistics of result in the background, replacing any
+// computation still running. Invalid results keep the previous statistics.
+func (a *App) refreshStats(result query.QueryResult) {
+	if !a.stats.visible || !result.IsValid {
+		return
+	}
+
+	if a.stats.cancel != nil {
+		a.stats.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.stats.cancel = cancel
+	a.stats.generation++
+	generation := a.stats.generation
+
+	a.stats.view.SetTitle(" Statistics (computing…) ")
+
+	raw := result.Raw
+	go func() {
+		summary, err := stats.Compute(ctx, raw)
+		if ctx.Err() != nil {
+			// A newer result replaced this one
+			return
+		}
+		a.tviewApp.QueueUpdateDraw(func() {
+			a.showStats(generation, summary, err)
+		})
+	}()
+}
+
+// showStats renders the statistics of the given computation unless a newer one was started
+func (a *App) showStats(generation int, summary *stats.Summary, err error) {
+	if generation != a.stats.generation {
+		return
+	}
+
+	a.stats.view.SetTitle(" Statistics ")
+	if err != nil {
+		a.stats.view.SetText(fmt.Sprintf("No statistics: %v", err))
+		return
+	}
+
+	// The panel has a fixed width, less its borders
+	a.stats.view.SetText(stats.Render(summary, statsPanelWidth-2))
+	a.stats.view.ScrollToBeginning()
+}
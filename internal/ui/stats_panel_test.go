@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gataky/dive/internal/stats"
+)
+
+func TestStatsPanel(t *testing.T) {
+	app := NewApp([]Document{{Name: "orders.json", JSONData: `{"orders": [{"total": 10}, {"total": 30}]}`}})
+
+	app.inputField.SetText("orders.#.total")
+	app.toggleStatsPanel()
+	if !app.stats.visible || app.stats.generation != 1 {
+		t.Fatalf("Expected a statistics computation to be started, got generation %d", app.stats.generation)
+	}
+
+	// Each valid result starts a new computation, invalid ones keep the last statistics
+	app.inputField.SetText("missing")
+	if app.stats.generation != 1 {
+		t.Errorf("Expected invalid path to keep the statistics, got generation %d", app.stats.generation)
+	}
+	app.inputField.SetText("orders.#.total")
+	generation := app.stats.generation
+	if generation != 2 {
+		t.Errorf("Expected generation 2, got %d", generation)
+	}
+
+	summary, err := stats.Compute(context.Background(), "[10, 30]")
+	app.showStats(generation-1, &stats.Summary{Count: 99}, nil)
+	if app.stats.view.GetText(false) != "" {
+		t.Errorf("Expected stale statistics to be dropped, got:\n%s", app.stats.view.GetText(false))
+	}
+
+	app.showStats(generation, summary, err)
+	if !strings.Contains(app.stats.view.GetText(false), "mean     20\n") {
+		t.Errorf("Expected statistics of the totals, got:\n%s", app.stats.view.GetText(false))
+	}
+
+	app.toggleStatsPanel()
+	if app.stats.visible || app.stats.cancel != nil {
+		t.Error("Expected the statistics panel to be hidden and its computation cancelled")
+	}
+}
@@ -18,13 +18,15 @@ type Theme struct {
 	TextDefault     tcell.Color // Default text color
 	TextPlaceholder tcell.Color // Placeholder text color
 	TextAccent      tcell.Color // Accent text color (for headers/highlights)
+	TextKey         tcell.Color // Key names in the footer and headings in the help panel
+	TextMuted       tcell.Color // Secondary text such as hints and inactive tabs
 
 	// Message colors
 	ColorSuccess tcell.Color // Success message color
 	ColorError   tcell.Color // Error message color
 }
 
-// DefaultTheme returns the default theme with terminal-friendly colors
+// DefaultTheme returns the default theme with terminal-friendly colors for dark terminals
 func DefaultTheme() *Theme {
 	return &Theme{
 		// Border colors
@@ -41,6 +43,8 @@ func DefaultTheme() *Theme {
 		TextDefault:     tcell.ColorDefault,
 		TextPlaceholder: tcell.ColorDefault,
 		TextAccent:      tcell.ColorYellow, // Keep yellow for header branding
+		TextKey:         tcell.ColorWhite,
+		TextMuted:       tcell.ColorGray,
 
 		// Message colors
 		ColorSuccess: tcell.ColorGreen,
@@ -0,0 +1,86 @@
+package theme
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Field is a theme color that can be set from the configuration file
+type Field struct {
+	Name  string // Configuration name, e.g. "border_focused"
+	Color *tcell.Color
+}
+
+// Fields returns the configurable colors of the theme in declaration order
+func (t *Theme) Fields() []Field {
+	return []Field{
+		{"border_focused", &t.BorderFocused},
+		{"border_unfocused", &t.BorderUnfocused},
+		{"border_valid", &t.BorderValid},
+		{"border_invalid", &t.BorderInvalid},
+		{"background", &t.Background},
+		{"field_background", &t.FieldBackground},
+		{"text_default", &t.TextDefault},
+		{"text_placeholder", &t.TextPlaceholder},
+		{"text_accent", &t.TextAccent},
+		{"text_key", &t.TextKey},
+		{"text_muted", &t.TextMuted},
+		{"color_success", &t.ColorSuccess},
+		{"color_error", &t.ColorError},
+	}
+}
+
+// Set changes the named color to value, a W3C color name, a #rrggbb hex
+// string or "default" for the terminal's own color
+func (t *Theme) Set(name, value string) error {
+	for _, field := range t.Fields() {
+		if field.Name == name {
+			color, err := ParseColor(value)
+			if err != nil {
+				return err
+			}
+			*field.Color = color
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown theme color %q", name)
+}
+
+// ParseColor parses a W3C color name, a #rrggbb hex string or "default"
+func ParseColor(value string) (tcell.Color, error) {
+	name := strings.ToLower(strings.TrimSpace(value))
+	if name == "default" {
+		return tcell.ColorDefault, nil
+	}
+	if color, ok := tcell.ColorNames[name]; ok {
+		return color, nil
+	}
+	if len(name) == 7 && name[0] == '#' {
+		if v, err := strconv.ParseInt(name[1:], 16, 32); err == nil {
+			return tcell.NewHexColor(int32(v)), nil
+		}
+	}
+	return tcell.ColorDefault, fmt.Errorf("invalid color %q: use a color name, #rrggbb or default", value)
+}
+
+// ColorName returns the name ParseColor accepts for a color. Among synonyms
+// such as gray and grey the alphabetically first name is used.
+func ColorName(color tcell.Color) string {
+	if color == tcell.ColorDefault {
+		return "default"
+	}
+
+	best := ""
+	for name, c := range tcell.ColorNames {
+		if c == color && (best == "" || name < best) {
+			best = name
+		}
+	}
+	if best == "" {
+		return strings.ToLower(color.CSS())
+	}
+	return best
+}
@@ -0,0 +1,126 @@
+package theme
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Names lists the built-in themes in the order they are cycled through
+var Names = []string{"dark", "light", "solarized", "high-contrast", "monochrome"}
+
+// Named returns the built-in theme with the given name
+func Named(name string) (*Theme, error) {
+	switch name {
+	case "dark":
+		return DefaultTheme(), nil
+	case "light":
+		return LightTheme(), nil
+	case "solarized":
+		return SolarizedTheme(), nil
+	case "high-contrast":
+		return HighContrastTheme(), nil
+	case "monochrome":
+		return MonochromeTheme(), nil
+	}
+	return nil, fmt.Errorf("unknown theme %q: use %s", name, strings.Join(Names, ", "))
+}
+
+// LightTheme returns a theme for terminals with a light background
+func LightTheme() *Theme {
+	return &Theme{
+		BorderFocused:   tcell.ColorSteelBlue,
+		BorderUnfocused: tcell.ColorDarkGray,
+		BorderValid:     tcell.ColorGreen,
+		BorderInvalid:   tcell.ColorRed,
+
+		Background:      tcell.ColorDefault,
+		FieldBackground: tcell.ColorDefault,
+
+		TextDefault:     tcell.ColorDefault,
+		TextPlaceholder: tcell.ColorGray,
+		TextAccent:      tcell.ColorDarkGoldenrod,
+		TextKey:         tcell.ColorBlack,
+		TextMuted:       tcell.ColorGray,
+
+		ColorSuccess: tcell.ColorGreen,
+		ColorError:   tcell.ColorRed,
+	}
+}
+
+// SolarizedTheme returns a theme using the Solarized accent colors, meant
+// for terminals with the Solarized dark background
+func SolarizedTheme() *Theme {
+	return &Theme{
+		BorderFocused:   tcell.NewHexColor(0x268bd2), // blue
+		BorderUnfocused: tcell.NewHexColor(0x586e75), // base01
+		BorderValid:     tcell.NewHexColor(0x859900), // green
+		BorderInvalid:   tcell.NewHexColor(0xdc322f), // red
+
+		Background:      tcell.ColorDefault,
+		FieldBackground: tcell.ColorDefault,
+
+		TextDefault:     tcell.NewHexColor(0x839496), // base0
+		TextPlaceholder: tcell.NewHexColor(0x586e75), // base01
+		TextAccent:      tcell.NewHexColor(0xb58900), // yellow
+		TextKey:         tcell.NewHexColor(0x93a1a1), // base1
+		TextMuted:       tcell.NewHexColor(0x657b83), // base00
+
+		ColorSuccess: tcell.NewHexColor(0x859900),
+		ColorError:   tcell.NewHexColor(0xdc322f),
+	}
+}
+
+// HighContrastTheme returns a theme of bright colors on a black background
+func HighContrastTheme() *Theme {
+	return &Theme{
+		BorderFocused:   tcell.ColorYellow,
+		BorderUnfocused: tcell.ColorWhite,
+		BorderValid:     tcell.ColorLime,
+		BorderInvalid:   tcell.ColorRed,
+
+		Background:      tcell.ColorBlack,
+		FieldBackground: tcell.ColorBlack,
+
+		TextDefault:     tcell.ColorWhite,
+		TextPlaceholder: tcell.ColorSilver,
+		TextAccent:      tcell.ColorYellow,
+		TextKey:         tcell.ColorAqua,
+		TextMuted:       tcell.ColorSilver,
+
+		ColorSuccess: tcell.ColorLime,
+		ColorError:   tcell.ColorRed,
+	}
+}
+
+// MonochromeTheme returns a theme that only uses the terminal's own colors,
+// as requested by the NO_COLOR convention. Focus is shown by double-line
+// borders and path validity by a ✓ or ✗ mark instead.
+func MonochromeTheme() *Theme {
+	return &Theme{
+		BorderFocused:   tcell.ColorDefault,
+		BorderUnfocused: tcell.ColorDefault,
+		BorderValid:     tcell.ColorDefault,
+		BorderInvalid:   tcell.ColorDefault,
+		Background:      tcell.ColorDefault,
+		FieldBackground: tcell.ColorDefault,
+		TextDefault:     tcell.ColorDefault,
+		TextPlaceholder: tcell.ColorDefault,
+		TextAccent:      tcell.ColorDefault,
+		TextKey:         tcell.ColorDefault,
+		TextMuted:       tcell.ColorDefault,
+		ColorSuccess:    tcell.ColorDefault,
+		ColorError:      tcell.ColorDefault,
+	}
+}
+
+// ShowsFocus reports whether focused borders have a color of their own
+func (t *Theme) ShowsFocus() bool {
+	return t.BorderFocused != t.BorderUnfocused
+}
+
+// ShowsValidity reports whether valid and invalid paths have border colors of their own
+func (t *Theme) ShowsValidity() bool {
+	return t.BorderValid != t.BorderInvalid
+}
@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gataky/dive/internal/ui/theme"
+	"github.com/rivo/tview"
+)
+
+// newTheme returns the named built-in theme with the configured colors applied
+func newTheme(name string, overrides map[string]string) (*theme.Theme, error) {
+	th, err := theme.Named(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range sortedNames(overrides) {
+		if err := th.Set(field, overrides[field]); err != nil {
+			return nil, fmt.Errorf("theme.%s: %w", field, err)
+		}
+	}
+	return th, nil
+}
+
+// setGlobalStyles makes components without explicit colors, such as the
+// layout containers and the save dialog frame, follow the theme
+func setGlobalStyles(th *theme.Theme) {
+	tview.Styles.PrimitiveBackgroundColor = th.Background
+	tview.Styles.PrimaryTextColor = th.TextDefault
+	tview.Styles.BorderColor = th.BorderUnfocused
+	tview.Styles.TitleColor = th.TextDefault
+
+	// Focus is shown by color, or by double-line borders in themes without one
+	focusBorders := [6]rune{
+		tview.BoxDrawingsDoubleHorizontal, tview.BoxDrawingsDoubleVertical,
+		tview.BoxDrawingsDoubleDownAndRight, tview.BoxDrawingsDoubleDownAndLeft,
+		tview.BoxDrawingsDoubleUpAndRight, tview.BoxDrawingsDoubleUpAndLeft,
+	}
+	if th.ShowsFocus() {
+		focusBorders = [6]rune{
+			tview.Borders.Horizontal, tview.Borders.Vertical,
+			tview.Borders.TopLeft, tview.Borders.TopRight,
+			tview.Borders.BottomLeft, tview.Borders.BottomRight,
+		}
+	}
+	tview.Borders.HorizontalFocus, tview.Borders.VerticalFocus = focusBorders[0], focusBorders[1]
+	tview.Borders.TopLeftFocus, tview.Borders.TopRightFocus = focusBorders[2], focusBorders[3]
+	tview.Borders.BottomLeftFocus, tview.Borders.BottomRightFocus = focusBorders[4], focusBorders[5]
+}
+
+// showValidity colors an input field's border by whether its path is valid.
+// Themes that give both the same color mark the field with ✓ or ✗ instead.
+func (a *App) showValidity(input *tview.InputField, valid bool) {
+	color, mark := a.theme.BorderInvalid, " ✗ "
+	if valid {
+		color, mark = a.theme.BorderValid, " ✓ "
+	}
+	if a.theme.ShowsValidity() {
+		mark = ""
+	}
+	input.SetBorderColor(color)
+	input.SetTitle(mark)
+}
+
+// cycleTheme switches to the next built-in theme
+func (a *App) cycleTheme() {
+	next := theme.Names[0]
+	for i, name := range theme.Names {
+		if name == a.themeName {
+			next = theme.Names[(i+1)%len(theme.Names)]
+		}
+	}
+	a.setTheme(next)
+}
+
+// setTheme switches to the named built-in theme
+func (a *App) setTheme(name string) {
+	th, err := newTheme(name, a.themeOverrides)
+	if err != nil {
+		a.showMessage(tview.Escape(err.Error()), true)
+		return
+	}
+	a.themeName = name
+	a.theme = th
+	a.applyTheme()
+	a.showMessage("Theme: "+name, false)
+}
+
+// applyTheme restyles every component and re-renders the markup that uses
+// theme colors after the theme changed
+func (a *App) applyTheme() {
+	setGlobalStyles(a.theme)
+
+	styleInputField(a.inputField, a.theme)
+	styleInputField(a.split.input, a.theme)
+	for _, view := range append([]*tview.TextView{a.outputPanel, a.split.output, a.footer, a.statusLine, a.breadcrumbs, a.tabBar, a.helpPanel, a.stats.view, a.pipe.view, a.inspector.view}, a.compareViews...) {
+		styleTextView(view, a.theme)
+	}
+	styleList(a.autocompleteDropdown, a.theme)
+	styleList(a.violationsList, a.theme)
+	styleInputField(a.palette.input, a.theme)
+	styleList(a.palette.list, a.theme)
+	styleList(a.history.visits, a.theme)
+	styleList(a.history.edits, a.theme)
+	styleBox(a.palette.view.Box, a.theme)
+
+	// Borders are unfocused now, so mark the focused component again
+	focused := a.focusedComponent
+	a.focusedComponent = FocusNone
+	a.setComponentFocus(focused)
+
+	a.helpPanel.SetText(a.helpText())
+	a.originalFooterText = a.footerText()
+	a.footer.SetText(a.originalFooterText)
+	a.updateTabBar()
+	a.validateDocument()
+	result := a.runQuery(a.inputField.GetText())
+
+	// Drop the previous theme's validity marks, showing the path's again
+	a.split.input.SetTitle("")
+	a.inputField.SetTitle("")
+	if a.inputField.GetText() != "" {
+		a.showValidity(a.inputField, result.IsValid)
+	}
+}
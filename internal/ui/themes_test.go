@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gataky/dive/internal/config"
+	"github.com/gataky/dive/internal/ui/theme"
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestNamedThemes(t *testing.T) {
+	for _, name := range theme.Names {
+		if _, err := newTheme(name, nil); err != nil {
+			t.Errorf("Expected built-in theme %q, got error: %v", name, err)
+		}
+	}
+	if _, err := newTheme("neon", nil); err == nil {
+		t.Error("Expected an error for an unknown theme")
+	}
+
+	th, err := newTheme("light", map[string]string{"text_key": "navy"})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if th.TextKey != tcell.ColorNavy {
+		t.Errorf("Expected the configured color to override the theme, got %v", th.TextKey)
+	}
+}
+
+func TestMonochromeTheme(t *testing.T) {
+	cfg := config.Default()
+	cfg.Defaults.Theme = "monochrome"
+	app, err := NewAppWithConfig([]Document{{Name: "a.json", JSONData: `{}`}, {Name: "b.json", JSONData: `{}`}}, cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	// Every color tag falls back to the terminal's colors
+	for _, text := range []string{app.footer.GetText(false), app.tabBar.GetText(false), app.helpPanel.GetText(false)} {
+		for _, color := range []string{"white", "gray", "yellow"} {
+			if strings.Contains(text, "["+color) {
+				t.Errorf("Expected no %s tags in the monochrome theme, got %q", color, text)
+			}
+		}
+	}
+
+	// Focus and validity are shown without colors
+	if tview.Borders.HorizontalFocus == tview.Borders.Horizontal {
+		t.Error("Expected focused borders to differ from unfocused ones")
+	}
+	app.inputField.SetText("nothing")
+	if title := app.inputField.GetTitle(); title != " ✗ " {
+		t.Errorf("Expected an invalid path to be marked with ✗, got %q", title)
+	}
+	app.inputField.SetText("")
+	app.setTheme("dark")
+	if tview.Borders.HorizontalFocus != tview.Borders.Horizontal {
+		t.Error("Expected the dark theme to show focus by color only")
+	}
+}
+
+func TestCycleTheme(t *testing.T) {
+	app := NewApp([]Document{{Name: "doc.json", JSONData: `{"a": 1}`}})
+
+	app.cycleTheme()
+	if app.themeName != "light" || app.theme.TextKey != tcell.ColorBlack {
+		t.Errorf("Expected the light theme after dark, got %q", app.themeName)
+	}
+	if !strings.Contains(app.originalFooterText, "[black::b]F1") {
+		t.Errorf("Expected the footer to use the light theme's key color, got %q", app.originalFooterText)
+	}
+
+	for range len(theme.Names) - 1 {
+		app.cycleTheme()
+	}
+	if app.themeName != "dark" {
+		t.Errorf("Expected cycling to wrap around to dark, got %q", app.themeName)
+	}
+}
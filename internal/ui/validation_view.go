@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gataky/dive/internal/schema"
+	"github.com/rivo/tview"
+)
+
+// LoadSchema compiles the JSON Schema at path, validates the active document
+// against it and shows the list of violations
+func (a *App) LoadSchema(path string) error {
+	validator, err := schema.LoadValidator(path)
+	if err != nil {
+		return err
+	}
+
+	a.validator = validator
+	a.violationsVisible = true
+	a.validateDocument()
+	a.rebuildLayout()
+	return nil
+}
+
+// setupViolationsPanel configures focus handling of the violations list
+func (a *App) setupViolationsPanel() {
+	a.violationsList.SetFocusFunc(func() {
+		a.setComponentFocus(FocusViolations)
+	})
+}
+
+// toggleViolationsPanel shows or hides the violations list, asking for a
+// schema file first if none was loaded
+func (a *App) toggleViolationsPanel() {
+	if a.validator == nil {
+		a.promptSchema()
+		return
+	}
+
+	a.violationsVisible = !a.violationsVisible
+	a.rebuildLayout()
+	if a.violationsVisible {
+		a.validateDocument()
+		a.tviewApp.SetFocus(a.violationsList)
+	} else {
+		a.tviewApp.SetFocus(a.inputField)
+	}
+}
+
+// promptSchema asks for a JSON Schema file and validates the active document against it
+func (a *App) promptSchema() {
+	current := ""
+	if a.validator != nil {
+		current = a.validator.Name
+	}
+
+	a.showPrompt(" Validate Against JSON Schema ", "Schema file: ", current, func(path string) {
+		if err := a.LoadSchema(path); err != nil {
+			a.showMessage(fmt.Sprintf("Error: %v", err), true)
+			return
+		}
+		a.tviewApp.SetFocus(a.violationsList)
+	})
+}
+
+// validateDocument validates the active document and lists the violations.
+// Selecting a violation moves the input field to its location.
+func (a *App) validateDocument() {
+	if a.validator == nil {
+		return
+	}
+
+	a.violationsList.Clear()
+
+	violations, err := a.validator.Validate(a.currentDocument().jsonData)
+	if err != nil {
+		a.violationsList.SetTitle(fmt.Sprintf(" %s ", tview.Escape(a.validator.Name)))
+		a.violationsList.AddItem(fmt.Sprintf("[%s]Error: %s[-]", a.theme.ColorError, tview.Escape(err.Error())), "", 0, nil)
+		return
+	}
+
+	if len(violations) == 0 {
+		a.violationsList.SetTitle(fmt.Sprintf(" Valid: %s ", tview.Escape(a.validator.Name)))
+		a.violationsList.AddItem(fmt.Sprintf("[%s]Document is valid[-]", a.theme.ColorSuccess), "", 0, nil)
+		return
+	}
+
+	a.violationsList.SetTitle(fmt.Sprintf(" %d violation(s): %s ", len(violations), tview.Escape(a.validator.Name)))
+	for _, violation := range violations {
+		v := violation
+		location := v.InstancePath
+		if location == "" {
+			location = "(root)"
+		}
+		text := fmt.Sprintf("[%s]%s[-]: %s", a.theme.ColorError, tview.Escape(location), tview.Escape(v.Message))
+		a.violationsList.AddItem(text, "", 0, func() {
+			a.jumpToPath(v.Path)
+		})
+	}
+}
+
+// jumpToPath sets the input field to path and focuses it
+func (a *App) jumpToPath(path string) {
+	a.inputField.SetText(path)
+	a.tviewApp.SetFocus(a.inputField)
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadSchemaListsViolations(t *testing.T) {
+	schemaPath := filepath.Join(t.TempDir(), "schema.json")
+	schemaJSON := `{"properties": {"users": {"items": {"properties": {"age": {"type": "integer"}}}}}}`
+	if err := os.WriteFile(schemaPath, []byte(schemaJSON), 0644); err != nil {
+		t.Fatalf("Failed to create schema file: %v", err)
+	}
+
+	app := NewApp([]Document{{Name: "users.json", JSONData: `{"users": [{"age": 1}, {"age": "two"}]}`}})
+	if err := app.LoadSchema(schemaPath); err != nil {
+		t.Fatalf("Expected schema to load, got: %v", err)
+	}
+
+	if !app.violationsVisible {
+		t.Error("Expected violations panel to be visible")
+	}
+	if app.violationsList.GetItemCount() != 1 {
+		t.Fatalf("Expected 1 violation, got %d", app.violationsList.GetItemCount())
+	}
+
+	main, _ := app.violationsList.GetItemText(0)
+	if !strings.Contains(main, "/users/1/age") {
+		t.Errorf("Expected violation at /users/1/age, got %q", main)
+	}
+
+	// Selecting the violation jumps to its location
+	app.jumpToPath("users.1.age")
+	if app.inputField.GetText() != "users.1.age" {
+		t.Errorf("Expected input field to jump to violation, got %q", app.inputField.GetText())
+	}
+	if app.outputPanel.GetText(false) != "two" {
+		t.Errorf("Expected output of the violating value, got %q", app.outputPanel.GetText(false))
+	}
+}
+
+func TestLoadSchemaMissingFile(t *testing.T) {
+	app := NewApp([]Document{{Name: "doc.json", JSONData: `{}`}})
+	if err := app.LoadSchema(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing schema file")
+	}
+	if app.violationsVisible {
+		t.Error("Expected violations panel to stay hidden")
+	}
+}
@@ -0,0 +1,318 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gataky/dive/internal/export"
+	"github.com/gataky/dive/internal/query"
+	"github.com/rivo/tview"
+	"github.com/tidwall/gjson"
+)
+
+// focusedView returns the focused output panel, or the main one if another component has focus
+func (a *App) focusedView() *tview.TextView {
+	if view, ok := a.tviewApp.GetFocus().(*tview.TextView); ok && view != a.helpPanel && view != a.footer {
+		return view
+	}
+	return a.outputPanel
+}
+
+// scrollOffset returns the first line shown by a view and its column offset.
+// Views that were not drawn yet report line -1.
+func scrollOffset(view *tview.TextView) (row, col int) {
+	row, col = view.GetScrollOffset()
+	return max(row, 0), col
+}
+
+// cursorFocused reports whether the focused output panel has a cursor to move
+// instead of scrolling
+func (a *App) cursorFocused() bool {
+	return a.focusedView() == a.outputPanel && a.cursorActive()
+}
+
+// scrollLines moves the cursor by n lines, up if n is negative, or scrolls the
+// focused output panel if it has no cursor
+func (a *App) scrollLines(n int) {
+	if a.cursorFocused() {
+		a.moveCursor(a.cursor + n)
+		return
+	}
+	view := a.focusedView()
+	row, col := scrollOffset(view)
+	view.ScrollTo(max(row+n, 0), col)
+}
+
+// scrollToLine moves the cursor to a line, or scrolls the focused output panel to it
+func (a *App) scrollToLine(line int) {
+	if a.cursorFocused() {
+		a.moveCursor(line)
+		return
+	}
+	view := a.focusedView()
+	_, col := scrollOffset(view)
+	view.ScrollTo(max(line, 0), col)
+}
+
+// scrollToTop moves the cursor to the first line, or scrolls to the top
+func (a *App) scrollToTop() {
+	if a.cursorFocused() {
+		a.moveCursor(0)
+		return
+	}
+	a.focusedView().ScrollToBeginning()
+}
+
+// scrollToBottom moves the cursor to the last line, or scrolls to the bottom
+func (a *App) scrollToBottom() {
+	if a.cursorFocused() {
+		a.moveCursor(len(a.outputLines) - 1)
+		return
+	}
+	a.focusedView().ScrollToEnd()
+}
+
+// scrollHalfPage scrolls the focused output panel by half its height in direction (1 or -1)
+func (a *App) scrollHalfPage(direction int) {
+	_, _, _, height := a.focusedView().GetInnerRect()
+	a.scrollLines(direction * max(height/2, 1))
+}
+
+// promptSearch asks for a pattern and scrolls to the next line containing it
+func (a *App) promptSearch() {
+	a.showPrompt(" Search ", "/", a.search, func(pattern string) {
+		a.search = pattern
+		a.findNext(1)
+	})
+}
+
+// findNext moves to the next line of the focused output panel containing the
+// last pattern searched for, or the previous one if direction is -1. The
+// search wraps around at either end like in vim.
+func (a *App) findNext(direction int) {
+	if a.search == "" {
+		a.showMessage("No previous search pattern", true)
+		return
+	}
+
+	view := a.focusedView()
+	lines := strings.Split(view.GetText(true), "\n")
+	row, _ := scrollOffset(view)
+	if a.cursorFocused() {
+		row = a.cursor
+	}
+	for step := 1; step <= len(lines); step++ {
+		line := row + direction*step
+		wrapped := line < 0 || line >= len(lines)
+		line = (line%len(lines) + len(lines)) % len(lines)
+		if !strings.Contains(lines[line], a.search) {
+			continue
+		}
+
+		a.scrollToLine(line)
+		switch {
+		case wrapped && direction > 0:
+			a.showMessage("Search hit BOTTOM, continuing at TOP", false)
+		case wrapped:
+			a.showMessage("Search hit TOP, continuing at BOTTOM", false)
+		}
+		return
+	}
+	a.showMessage("Pattern not found: "+tview.Escape(a.search), true)
+}
+
+// activeEngine returns the query engine of the pane used last
+func (a *App) activeEngine() *query.Engine {
+	if a.split.visible && a.split.active {
+		return a.split.engine
+	}
+	return a.currentDocument().queryEngine
+}
+
+// yanked returns the path and value under the cursor, or of the current
+// result of the pane used last when there is no cursor
+func (a *App) yanked() (string, string) {
+	if !a.cursorActive() {
+		engine := a.activeEngine()
+		return engine.GetLastValidPath(), engine.GetLastValidValue()
+	}
+	path, value := a.inspectedValue()
+	return path, query.FormatValue(value)
+}
+
+// yankValue copies the value under the cursor or the current result to the clipboard
+func (a *App) yankValue() {
+	_, value := a.yanked()
+	a.yank(value, "value")
+}
+
+// yankPath copies the path under the cursor or of the current result to the clipboard
+func (a *App) yankPath() {
+	path, _ := a.yanked()
+	if path == "" {
+		a.showMessage("The whole document has no path", true)
+		return
+	}
+	a.yank(path, "path")
+}
+
+// yank copies text to the clipboard and reports what was copied
+func (a *App) yank(text, what string) {
+	if err := export.CopyToClipboard(text); err != nil {
+		a.showMessage(fmt.Sprintf("Error: %v", err), true)
+		return
+	}
+	a.showMessage("Copied "+what, false)
+}
+
+// foldable reports whether the cursor is on a result whose values can be folded
+func (a *App) foldable() bool {
+	if !a.cursorActive() {
+		a.showMessage("Folding needs the cursor of a single result", true)
+		return false
+	}
+	return true
+}
+
+// fold collapses the object or array under the cursor, or the closest one
+// around it that is still expanded
+func (a *App) fold() {
+	if !a.foldable() {
+		return
+	}
+	result := gjson.Parse(a.currentDocument().queryEngine.GetLastValidRaw())
+	path := a.linePaths[a.cursor]
+	for {
+		value := result
+		if path != "" {
+			value = result.Get(path)
+		}
+		if query.Collapsible(value) && !a.collapsed[path] {
+			break
+		}
+		if path == "" {
+			a.showMessage("Nothing to fold", true)
+			return
+		}
+		path, _ = query.ParentPath(path)
+	}
+	a.collapsed[path] = true
+	a.refold(path)
+	a.showMessage("Folded", false)
+}
+
+// unfold expands the object or array under the cursor
+func (a *App) unfold() {
+	if !a.foldable() {
+		return
+	}
+	path := a.linePaths[a.cursor]
+	if !a.collapsed[path] {
+		a.showMessage("Nothing folded here", true)
+		return
+	}
+	delete(a.collapsed, path)
+	a.refold(path)
+	a.showMessage("Unfolded", false)
+}
+
+// unfoldAll expands every object and array of the result
+func (a *App) unfoldAll() {
+	if !a.foldable() {
+		return
+	}
+	path := a.linePaths[a.cursor]
+	clear(a.collapsed)
+	a.refold(path)
+	a.showMessage("Unfolded", false)
+}
+
+// refold shows the result with the collapsed values folded and the cursor on
+// the line of the value at path
+func (a *App) refold(path string) {
+	a.runQuery(a.inputField.GetText())
+	a.moveCursorToPath(path)
+}
+
+// promptCommand asks for a command like vim's command line
+func (a *App) promptCommand() {
+	a.showPrompt(" Command ", ":", "", a.runCommand)
+}
+
+// runCommand runs a command entered after ":": w [file], q, q!, wq [file],
+// set [no]wrap, colorscheme <theme> or a line number to jump to
+func (a *App) runCommand(command string) {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return
+	}
+	name, args := fields[0], fields[1:]
+
+	if line, err := strconv.Atoi(name); err == nil && len(args) == 0 {
+		a.scrollToLine(line - 1)
+		return
+	}
+
+	switch {
+	case name == "q" || name == "qa" || name == "quit":
+		a.quit()
+	case name == "q!" || name == "qa!":
+		a.Stop()
+	case name == "w" || name == "write":
+		a.writeOutput(args)
+	case name == "wq" || name == "x":
+		if a.writeOutput(args) {
+			a.Stop()
+		}
+	case name == "set" && len(args) == 1:
+		a.setOption(args[0])
+	case name == "colorscheme" && len(args) == 1:
+		a.setTheme(args[0])
+	default:
+		a.showMessage("Not an editor command: "+tview.Escape(command), true)
+	}
+}
+
+// writeOutput saves the output to the file named by args, or to the
+// configured save filename, and reports whether it succeeded
+func (a *App) writeOutput(args []string) bool {
+	filename := a.saveFilename
+	if len(args) > 0 {
+		filename = strings.Join(args, " ")
+	}
+	if err := export.SaveToFile(a.outputText(), filename); err != nil {
+		a.showMessage(fmt.Sprintf("Error: %v", err), true)
+		return false
+	}
+	a.showMessage(fmt.Sprintf("Saved to %s", filename), false)
+	return true
+}
+
+// setOption changes an option with vim's set syntax
+func (a *App) setOption(option string) {
+	switch option {
+	case "wrap":
+		a.setWrap(true)
+	case "nowrap":
+		a.setWrap(false)
+	case "wrap!", "invwrap":
+		a.setWrap(!a.wrap)
+	default:
+		a.showMessage("Unknown option: "+tview.Escape(option), true)
+	}
+}
+
+// setWrap turns wrapping of long lines in the output panels on or off
+func (a *App) setWrap(wrap bool) {
+	a.wrap = wrap
+	for _, view := range append([]*tview.TextView{a.split.output}, a.compareViews...) {
+		view.SetWrap(wrap)
+	}
+	a.updateWrap()
+	if wrap {
+		a.showMessage("wrap", false)
+	} else {
+		a.showMessage("nowrap", false)
+	}
+}
@@ -0,0 +1,185 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gataky/dive/internal/config"
+	"github.com/gdamore/tcell/v2"
+)
+
+// newVimApp returns an app in vim mode with the output panel focused
+func newVimApp(t *testing.T, jsonData string) *App {
+	t.Helper()
+	cfg := config.Default()
+	cfg.Defaults.Vim = true
+	app, err := NewAppWithConfig([]Document{{Name: "doc.json", JSONData: jsonData}}, cfg)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	app.tviewApp.SetFocus(app.outputPanel)
+	return app
+}
+
+// typeKeys dispatches characters to the output panel
+func typeKeys(app *App, keys string) {
+	for _, r := range keys {
+		app.dispatch(contextOutput, tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+}
+
+func TestVimScrolling(t *testing.T) {
+	app := newVimApp(t, `{"a": 1, "b": 2, "c": 3, "d": 4}`)
+
+	typeKeys(app, "jjk")
+	if app.cursor != 1 {
+		t.Errorf("Expected the cursor on line 1, got %d", app.cursor)
+	}
+
+	// An unfinished sequence swallows the key that doesn't continue it
+	typeKeys(app, "gx")
+	if app.cursor != 1 || app.pendingKeys != nil {
+		t.Errorf("Expected the sequence to be cancelled, got line %d", app.cursor)
+	}
+
+	typeKeys(app, "G")
+	if app.cursor != 5 {
+		t.Errorf("Expected G to move to the last line, got %d", app.cursor)
+	}
+	typeKeys(app, "gg")
+	if app.cursor != 0 {
+		t.Errorf("Expected gg to move to the top, got %d", app.cursor)
+	}
+
+	app.search = `"c"`
+	typeKeys(app, "n")
+	if app.cursor != 3 {
+		t.Errorf("Expected n to move to the match, got %d", app.cursor)
+	}
+
+	// Views without a cursor scroll instead
+	app.setOutputView(ViewSchema)
+	typeKeys(app, "jjk")
+	if row, _ := app.outputPanel.GetScrollOffset(); row != 1 {
+		t.Errorf("Expected to scroll to line 1, got %d", row)
+	}
+}
+
+func TestVimFold(t *testing.T) {
+	app := newVimApp(t, `{"a": {"b": [1, 2]}, "c": [3]}`)
+
+	// zc on a number folds the array around it
+	typeKeys(app, "jjjzc")
+	text := app.outputPanel.GetText(true)
+	if !strings.Contains(text, `"b": [… 2 items]`) || !strings.Contains(text, `"c": [`) {
+		t.Errorf("Expected only the array under the cursor to be folded, got %q", text)
+	}
+	if path := app.linePaths[app.cursor]; path != "a.b" {
+		t.Errorf("Expected the cursor on the folded array, got %q", path)
+	}
+	if !strings.Contains(app.outputText(), "1") {
+		t.Errorf("Expected the whole result to be exported, got %q", app.outputText())
+	}
+
+	// zc on a folded value folds the one around it
+	typeKeys(app, "zc")
+	if text := app.outputPanel.GetText(true); !strings.Contains(text, `"a": {… 1 key}`) {
+		t.Errorf("Expected the object around the array to be folded, got %q", text)
+	}
+
+	typeKeys(app, "zo")
+	if text := app.outputPanel.GetText(true); !strings.Contains(text, `"b": [… 2 items]`) {
+		t.Errorf("Expected zo to unfold only the object under the cursor, got %q", text)
+	}
+	typeKeys(app, "zR")
+	if text := app.outputPanel.GetText(true); strings.Contains(text, "…") {
+		t.Errorf("Expected the result to be unfolded, got %q", text)
+	}
+
+	// A new path starts unfolded
+	typeKeys(app, "zc")
+	app.inputField.SetText("a")
+	if text := app.outputPanel.GetText(true); strings.Contains(text, "…") || len(app.collapsed) != 0 {
+		t.Errorf("Expected a new path to be unfolded, got %q", text)
+	}
+}
+
+func TestVimYankUnderCursor(t *testing.T) {
+	app := newVimApp(t, `{"a": {"b": [1, 2]}}`)
+
+	if path, value := app.yanked(); path != "" || !strings.Contains(value, `"b"`) {
+		t.Errorf("Expected the whole result at the top line, got %q and %q", path, value)
+	}
+	typeKeys(app, "jj")
+	if path, value := app.yanked(); path != "a.b" || value != "[\n  1,\n  2\n]" {
+		t.Errorf("Expected the array under the cursor, got %q and %q", path, value)
+	}
+	typeKeys(app, "j")
+	if path, value := app.yanked(); path != "a.b.0" || value != "1" {
+		t.Errorf("Expected the number under the cursor, got %q and %q", path, value)
+	}
+}
+
+func TestVimCommands(t *testing.T) {
+	app := newVimApp(t, `{"a": 1}`)
+
+	app.runCommand("set nowrap")
+	if app.wrap {
+		t.Error("Expected :set nowrap to turn wrapping off")
+	}
+	app.runCommand("colorscheme light")
+	if app.themeName != "light" {
+		t.Errorf("Expected :colorscheme to switch themes, got %q", app.themeName)
+	}
+
+	filename := filepath.Join(t.TempDir(), "out.json")
+	app.runCommand("w " + filename)
+	if data, err := os.ReadFile(filename); err != nil || !strings.Contains(string(data), `"a"`) {
+		t.Errorf("Expected :w to save the output, got %q, %v", data, err)
+	}
+}
+
+func TestVimKeymap(t *testing.T) {
+	// Without vim mode the vim keys are unbound and n still jumps between changes
+	km, err := newKeymap(nil, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if km.label("scroll_top") != "" || km.label("next_change") != "n" {
+		t.Errorf("Expected default keys, got %q and %q", km.label("scroll_top"), km.label("next_change"))
+	}
+
+	km, err = newKeymap(nil, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if km.label("scroll_top") != "gg" || km.label("next_change") != "]c" || km.label("unfold_all") != "zR" {
+		t.Errorf("Expected vim keys, got %q, %q and %q", km.label("scroll_top"), km.label("next_change"), km.label("unfold_all"))
+	}
+
+	tests := []struct {
+		keys     map[string][]string
+		expected string
+	}{
+		{map[string][]string{"scroll_down": {"g"}}, "keys.scroll_down: g overlaps gg of scroll_top"},
+		{map[string][]string{"search": {"n"}}, "keys.search: n is already bound to search_next"},
+		{map[string][]string{"yank_path": {"y y"}}, "keys.yank_path: yy is already bound to yank_value"},
+	}
+	for _, tt := range tests {
+		if err := CheckKeys(tt.keys, true); err == nil || !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("Expected error containing %q for %v, got %v", tt.expected, tt.keys, err)
+		}
+	}
+}
+
+func TestVimEscapeFocusesOutput(t *testing.T) {
+	app := newVimApp(t, `{"a": 1}`)
+	app.tviewApp.SetFocus(app.inputField)
+
+	app.dispatch(contextInput, tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	if app.tviewApp.GetFocus() != app.outputPanel {
+		t.Error("Expected Esc to focus the output panel")
+	}
+}
@@ -1,55 +1,433 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/gataky/dive/internal/config"
+	"github.com/gataky/dive/internal/diff"
+	"github.com/gataky/dive/internal/export"
 	"github.com/gataky/dive/internal/input"
+	"github.com/gataky/dive/internal/query"
+	"github.com/gataky/dive/internal/schema"
+	"github.com/gataky/dive/internal/stats"
 	"github.com/gataky/dive/internal/ui"
+	"github.com/gataky/dive/internal/ui/theme"
 )
 
 func main() {
-	// Read JSON data from file or stdin
-	var jsonData string
-	var err error
+	// The config command must work even when the configuration file is invalid
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		runConfig(os.Args[2:])
+		return
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
 	if len(os.Args) > 1 {
-		// File path provided as argument
-		filePath := os.Args[1]
-		jsonData, err = input.ReadFromFile(filePath)
+		switch os.Args[1] {
+		case "diff":
+			runDiff(os.Args[2:], cfg)
+			return
+		case "validate":
+			runValidate(os.Args[2:])
+			return
+		case "stats":
+			runStats(os.Args[2:])
+			return
+		}
+	}
+
+	flags := flag.NewFlagSet("dive", flag.ExitOnError)
+	schemaPath := flags.String("schema", "", "validate documents against this JSON Schema file")
+	themeName := flags.String("theme", "", "color theme: "+strings.Join(theme.Names, ", "))
+	lenient := flags.Bool("lenient", false, "accept comments, trailing commas, single quotes and unquoted keys as in .jsonc and .json5 files")
+	flags.Usage = printUsage
+	files := parseFlags(flags, os.Args[1:])
+	input.Lenient = *lenient
+
+	if err := selectTheme(cfg, *themeName); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Read JSON data from files or stdin
+	var documents []ui.Document
+
+	if len(files) > 0 {
+		// File paths or glob patterns provided as arguments
+		documents, err = readDocuments(files)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error reading file: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 	} else {
-		// No argument provided, try reading from stdin
-		jsonData, err = input.ReadFromStdin()
-		if err != nil {
-			// No piped input
+		// No argument provided, try reading from stdin unless nothing is piped
+		if stdinIsTerminal() {
+			printUsage()
+			os.Exit(1)
+		}
+		content, err := input.ReadStdinContent()
+		if errors.Is(err, input.ErrNoStdin) {
 			printUsage()
 			os.Exit(1)
 		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		documents = append(documents, ui.Document{Name: "stdin", JSONData: content.JSON, Compression: content.Compression, Relaxed: content.Relaxed})
 	}
 
 	// Validate that we have non-empty JSON data
-	if jsonData == "" {
-		fmt.Fprintf(os.Stderr, "Error: empty JSON data\n")
-		os.Exit(1)
+	for _, doc := range documents {
+		if doc.JSONData == "" {
+			fmt.Fprintf(os.Stderr, "Error: empty JSON data in %s\n", doc.Name)
+			os.Exit(1)
+		}
 	}
 
 	// Initialize and run the UI
-	app := ui.NewApp(jsonData)
+	app, err := ui.NewAppWithConfig(documents, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *schemaPath != "" {
+		if err := app.LoadSchema(*schemaPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if err := app.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error running application: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// loadConfig reads the configuration file, applies its process-wide settings
+// and registers the plugin modifiers it defines if plugins are enabled
+func loadConfig() (*config.Config, error) {
+	path, err := config.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := ui.CheckKeys(cfg.Keys, cfg.Defaults.Vim); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	query.Indent = strings.Repeat(" ", cfg.Defaults.Indent)
+	if err := export.SetClipboard(cfg.Defaults.Clipboard, cfg.Defaults.ClipboardCommand); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	if !cfg.Plugins.Enabled {
+		return cfg, nil
+	}
+
+	plugins := make([]query.Plugin, 0, len(cfg.Plugins.Modifiers))
+	for _, name := range cfg.PluginNames() {
+		m := cfg.Plugins.Modifiers[name]
+		timeout := m.Timeout
+		if timeout == 0 {
+			timeout = cfg.Plugins.Timeout
+		}
+		plugins = append(plugins, query.Plugin{
+			Name:        name,
+			Description: m.Description,
+			Command:     m.Command,
+			Expression:  m.Expression,
+			Timeout:     timeout,
+		})
+	}
+	return cfg, query.RegisterPlugins(plugins)
+}
+
+// selectTheme picks the theme named by the --theme flag, $DIVE_THEME or the
+// configuration file, in that order. Without any of them NO_COLOR selects the
+// monochrome theme.
+func selectTheme(cfg *config.Config, flagTheme string) error {
+	name := flagTheme
+	if name == "" {
+		name = os.Getenv("DIVE_THEME")
+	}
+	if name == "" {
+		name = cfg.Defaults.Theme
+	}
+	if name == "" && os.Getenv("NO_COLOR") != "" {
+		name = "monochrome"
+	}
+
+	if name != "" {
+		if _, err := theme.Named(name); err != nil {
+			return err
+		}
+	}
+	cfg.Defaults.Theme = name
+	return nil
+}
+
+// parseFlags parses the flags wherever they appear among args, so they may
+// follow the files, and returns the other arguments. Everything after "--" is
+// an argument.
+func parseFlags(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		flags.Parse(args)
+		rest := flags.Args()
+		if parsed := len(args) - len(rest); len(rest) == 0 || parsed > 0 && args[parsed-1] == "--" {
+			return append(positional, rest...)
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// runConfig prints the default configuration or the location of the configuration file
+func runConfig(args []string) {
+	flags := flag.NewFlagSet("config", flag.ExitOnError)
+	printDefault := flags.Bool("print-default", false, "print the default configuration")
+	printPath := flags.Bool("path", false, "print the location of the configuration file")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: dive config --print-default | --path\n")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	switch {
+	case *printDefault && !*printPath:
+		cfg := config.Default()
+		cfg.Keys = ui.DefaultKeys(cfg.Defaults.Vim)
+		if err := cfg.Write(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	case *printPath && !*printDefault:
+		path, err := config.DefaultPath()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(path)
+	default:
+		flags.Usage()
+		os.Exit(1)
+	}
+}
+
+// readDocuments expands glob patterns and reads every matching JSON file
+func readDocuments(args []string) ([]ui.Document, error) {
+	paths, err := input.ExpandPaths(args)
+	if err != nil {
+		return nil, err
+	}
+
+	documents := make([]ui.Document, 0, len(paths))
+	for _, filePath := range paths {
+		content, err := input.ReadFileContent(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading file: %w", err)
+		}
+		documents = append(documents, ui.Document{Name: filePath, Path: filePath, JSONData: content.JSON, Compression: content.Compression, Relaxed: content.Relaxed})
+	}
+
+	return documents, nil
+}
+
+// runDiff opens the structural diff view for two JSON files
+func runDiff(args []string, cfg *config.Config) {
+	flags := flag.NewFlagSet("diff", flag.ExitOnError)
+	arrayKey := flags.String("array-key", "", "match array elements by this object field instead of by index")
+	themeName := flags.String("theme", "", "color theme: "+strings.Join(theme.Names, ", "))
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: dive diff [--array-key <field>] [--theme <name>] <old-json-file> <new-json-file>\n")
+		flags.PrintDefaults()
+	}
+	args = parseFlags(flags, args)
+
+	if err := selectTheme(cfg, *themeName); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(args) != 2 {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	documents, err := readDocuments(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(documents) != 2 {
+		fmt.Fprintf(os.Stderr, "Error: expected two files, got %d\n", len(documents))
+		os.Exit(1)
+	}
+
+	app, err := ui.NewDiffAppWithConfig(documents[0], documents[1], diff.Options{ArrayKey: *arrayKey}, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 	if err := app.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error running application: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// validationResult is the machine-readable validation outcome of one file
+type validationResult struct {
+	File       string             `json:"file"`
+	Valid      bool               `json:"valid"`
+	Violations []schema.Violation `json:"violations"`
+}
+
+// runValidate validates JSON files against a JSON Schema without starting the UI.
+// It exits with 0 if every file is valid, 1 if there are violations and 2 on errors.
+func runValidate(args []string) {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	schemaPath := flags.String("schema", "", "JSON Schema file to validate against (required)")
+	format := flags.String("format", "text", "output format: text or json")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: dive validate --schema <schema-file> [--format text|json] <json-file>...\n")
+		flags.PrintDefaults()
+	}
+	args = parseFlags(flags, args)
+
+	if *schemaPath == "" || len(args) == 0 || (*format != "text" && *format != "json") {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	validator, err := schema.LoadValidator(*schemaPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	documents, err := readDocuments(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	results := make([]validationResult, 0, len(documents))
+	allValid := true
+	for _, doc := range documents {
+		violations, err := validator.Validate(doc.JSONData)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", doc.Name, err)
+			os.Exit(2)
+		}
+		results = append(results, validationResult{File: doc.Name, Valid: len(violations) == 0, Violations: violations})
+		allValid = allValid && len(violations) == 0
+	}
+
+	if *format == "json" {
+		output, _ := json.MarshalIndent(results, "", "  ")
+		fmt.Println(string(output))
+	} else {
+		for _, result := range results {
+			if result.Valid {
+				fmt.Printf("%s: valid\n", result.File)
+				continue
+			}
+			for _, violation := range result.Violations {
+				location := violation.InstancePath
+				if location == "" {
+					location = "/"
+				}
+				fmt.Printf("%s: %s: %s\n", result.File, location, violation.Message)
+			}
+		}
+	}
+
+	if !allValid {
+		os.Exit(1)
+	}
+}
+
+// runStats prints statistics of the array at a path without starting the UI
+func runStats(args []string) {
+	flags := flag.NewFlagSet("stats", flag.ExitOnError)
+	format := flags.String("format", "text", "output format: text or json")
+	width := flags.Int("width", 60, "width of the histogram in columns")
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: dive stats [--format text|json] [--width <columns>] <path> [json-file]\n")
+		flags.PrintDefaults()
+	}
+	args = parseFlags(flags, args)
+
+	if len(args) < 1 || len(args) > 2 || (*format != "text" && *format != "json") {
+		flags.Usage()
+		os.Exit(1)
+	}
+
+	var jsonData string
+	var err error
+	if len(args) == 2 {
+		jsonData, err = input.ReadFromFile(args[1])
+	} else {
+		jsonData, err = input.ReadFromStdin()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	result := query.NewEngine(jsonData).Query(args[0])
+	if !result.IsValid {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", result.Error)
+		os.Exit(1)
+	}
+
+	summary, err := stats.Compute(context.Background(), result.Raw)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *format == "json" {
+		output, _ := json.MarshalIndent(summary, "", "  ")
+		fmt.Println(string(output))
+	} else {
+		fmt.Print(stats.Render(summary, *width))
+	}
+}
+
+// stdinIsTerminal reports whether stdin is a terminal rather than piped input
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
 func printUsage() {
-	fmt.Fprintf(os.Stderr, "Usage: dive <json-file>\n")
+	fmt.Fprintf(os.Stderr, "Usage: dive [--schema <schema-file>] [--theme <name>] [--lenient] <json-file>...\n")
+	fmt.Fprintf(os.Stderr, "   or: dive '<glob-pattern>'\n")
+	fmt.Fprintf(os.Stderr, "   or: dive diff [--array-key <field>] [--theme <name>] <old-json-file> <new-json-file>\n")
+	fmt.Fprintf(os.Stderr, "   or: dive validate --schema <schema-file> [--format text|json] <json-file>...\n")
+	fmt.Fprintf(os.Stderr, "   or: dive stats [--format text|json] <path> [json-file]\n")
+	fmt.Fprintf(os.Stderr, "   or: dive config --print-default | --path\n")
 	fmt.Fprintf(os.Stderr, "   or: cat <json-file> | dive\n")
 	fmt.Fprintf(os.Stderr, "\n")
 	fmt.Fprintf(os.Stderr, "dive - Interactive JSON Viewer\n")
 	fmt.Fprintf(os.Stderr, "\n")
-	fmt.Fprintf(os.Stderr, "Provide a JSON file as an argument or pipe JSON data via stdin.\n")
+	fmt.Fprintf(os.Stderr, "Provide one or more JSON files as arguments or pipe JSON data via stdin.\n")
+	fmt.Fprintf(os.Stderr, "Each file is opened in its own tab.\n")
+	fmt.Fprintf(os.Stderr, "Compressed input is decompressed, and .jsonc and .json5 files may have comments.\n")
 }